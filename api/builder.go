@@ -0,0 +1,208 @@
+// Copyright 2025 Antti Kivi
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"maps"
+	"slices"
+	"sync/atomic"
+)
+
+// ManifestBuilder builds a Manifest in code with chainable methods. The zero
+// ManifestBuilder is ready to use and starts with an empty Manifest. Every
+// call to [ManifestBuilder.Build] returns a new Manifest that does not share
+// its slices with the builder or with the other built manifests, so
+// the builder can be reused, for example, for building variations of
+// the same manifest.
+type ManifestBuilder struct {
+	m Manifest
+}
+
+// NewManifestBuilder returns a new [ManifestBuilder] for a plugin with
+// the given name and domain.
+func NewManifestBuilder(name, domain string) *ManifestBuilder {
+	b := &ManifestBuilder{} //nolint:exhaustruct // starts with an empty manifest
+
+	return b.WithName(name).WithDomain(domain)
+}
+
+// WithName sets the Name of the manifest.
+func (b *ManifestBuilder) WithName(name string) *ManifestBuilder {
+	b.m.Name = name
+
+	return b
+}
+
+// WithDomain sets the Domain of the manifest.
+func (b *ManifestBuilder) WithDomain(domain string) *ManifestBuilder {
+	b.m.Domain = domain
+
+	return b
+}
+
+// WithDescription sets the Description of the manifest.
+func (b *ManifestBuilder) WithDescription(description string) *ManifestBuilder {
+	b.m.Description = description
+
+	return b
+}
+
+// WithVersion sets the Version of the manifest.
+func (b *ManifestBuilder) WithVersion(version string) *ManifestBuilder {
+	b.m.Version = version
+
+	return b
+}
+
+// WithExecutable sets the Executable of the manifest.
+func (b *ManifestBuilder) WithExecutable(executable string) *ManifestBuilder {
+	b.m.Executable = executable
+
+	return b
+}
+
+// WithEnvPrefix sets the EnvPrefix of the manifest.
+func (b *ManifestBuilder) WithEnvPrefix(prefix string) *ManifestBuilder {
+	b.m.EnvPrefix = prefix
+
+	return b
+}
+
+// WithCapabilities appends the given capabilities to the Capabilities of
+// the manifest.
+func (b *ManifestBuilder) WithCapabilities(capabilities ...string) *ManifestBuilder {
+	b.m.Capabilities = append(b.m.Capabilities, capabilities...)
+
+	return b
+}
+
+// AddConfig appends the given entries to the plugin-level Config of
+// the manifest.
+func (b *ManifestBuilder) AddConfig(entries ...ConfigEntry) *ManifestBuilder {
+	b.m.Config = append(b.m.Config, entries...)
+
+	return b
+}
+
+// AddGlobalConfig appends the given entries to the GlobalConfig of
+// the manifest.
+func (b *ManifestBuilder) AddGlobalConfig(entries ...ConfigEntry) *ManifestBuilder {
+	b.m.GlobalConfig = append(b.m.GlobalConfig, entries...)
+
+	return b
+}
+
+// AddCommand appends the given command to the Commands of the manifest.
+func (b *ManifestBuilder) AddCommand(c Command) *ManifestBuilder {
+	b.m.Commands = append(b.m.Commands, c)
+
+	return b
+}
+
+// AddTask appends the given task to the Tasks of the manifest.
+func (b *ManifestBuilder) AddTask(t Task) *ManifestBuilder {
+	b.m.Tasks = append(b.m.Tasks, t)
+
+	return b
+}
+
+// Build returns a copy of the built manifest after validating it with
+// [Manifest.Validate]. It returns the validation error if the manifest is not
+// valid.
+func (b *ManifestBuilder) Build() (Manifest, error) {
+	m := cloneManifest(&b.m)
+
+	if err := m.Validate(); err != nil {
+		return Manifest{}, err
+	}
+
+	return m, nil
+}
+
+// cloneManifest returns a copy of m that does not share its slices, maps, or
+// pointers with m. The values of the KeyValues are shared.
+func cloneManifest(m *Manifest) Manifest {
+	result := *m
+	result.Config = cloneEntries(m.Config)
+	result.GlobalConfig = cloneEntries(m.GlobalConfig)
+	result.Capabilities = slices.Clone(m.Capabilities)
+	result.Commands = slices.Clone(m.Commands)
+	result.Tasks = slices.Clone(m.Tasks)
+	result.DefaultLogLevel = clonePtr(m.DefaultLogLevel)
+	result.patterns = atomic.Value{}
+
+	for i, c := range result.Commands {
+		result.Commands[i].Aliases = slices.Clone(c.Aliases)
+		result.Commands[i].Config = cloneEntries(c.Config)
+		result.Commands[i].MutexGroups = slices.Clone(c.MutexGroups)
+		result.Commands[i].Requires = maps.Clone(c.Requires)
+
+		for j, group := range c.MutexGroups {
+			result.Commands[i].MutexGroups[j] = slices.Clone(group)
+		}
+
+		for k, v := range c.Requires {
+			result.Commands[i].Requires[k] = slices.Clone(v)
+		}
+	}
+
+	for i, t := range result.Tasks {
+		result.Tasks[i].Aliases = slices.Clone(t.Aliases)
+		result.Tasks[i].Config = cloneEntries(t.Config)
+		result.Tasks[i].Inputs = slices.Clone(t.Inputs)
+		result.Tasks[i].Outputs = slices.Clone(t.Outputs)
+	}
+
+	return result
+}
+
+// cloneEntries returns a copy of entries in which the Flags, Min, Max,
+// AllowedValues, and Fields are also copied.
+func cloneEntries(entries []ConfigEntry) []ConfigEntry {
+	entries = slices.Clone(entries)
+
+	for i, e := range entries {
+		entries[i].Flag = clonePtr(e.Flag)
+		entries[i].Min = clonePtr(e.Min)
+		entries[i].Max = clonePtr(e.Max)
+		entries[i].AllowedValues = slices.Clone(e.AllowedValues)
+		entries[i].Fields = cloneFields(e.Fields)
+	}
+
+	return entries
+}
+
+// cloneFields returns a copy of fields in which the nested Fields are also
+// copied.
+func cloneFields(fields []KeyValue) []KeyValue {
+	fields = slices.Clone(fields)
+
+	for i, f := range fields {
+		fields[i].Fields = cloneFields(f.Fields)
+	}
+
+	return fields
+}
+
+// clonePtr returns a pointer to a copy of *p or nil if p is nil.
+func clonePtr[T any](p *T) *T {
+	if p == nil {
+		return nil
+	}
+
+	v := *p
+
+	return &v
+}
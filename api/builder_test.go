@@ -0,0 +1,122 @@
+// Copyright 2025 Antti Kivi
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api_test
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/reginald-project/reginald-sdk-go/api"
+)
+
+func TestManifestBuilder(t *testing.T) {
+	t.Parallel()
+
+	format := api.ConfigEntry{
+		KeyValue: api.KeyValue{Key: "format", Value: "text", Type: api.StringValue},
+		Flag:     &api.Flag{Shorthand: "f"},
+	}
+
+	one := 1.0
+	limit := api.ConfigEntry{
+		KeyValue:      api.KeyValue{Key: "limit", Value: 1, Type: api.IntValue},
+		AllowedValues: []any{1, 2},
+		Min:           &one,
+	}
+	owner := api.ConfigEntry{
+		KeyValue: api.KeyValue{
+			Key:    "owner",
+			Type:   api.ObjectValue,
+			Fields: []api.KeyValue{{Key: "name", Value: "", Type: api.StringValue}},
+		},
+	}
+
+	list := api.Command{Name: "list", Aliases: []string{"ls"}, Config: []api.ConfigEntry{format}}
+
+	b := api.NewManifestBuilder("Example", "example").
+		WithDescription("Manage examples.").
+		WithVersion("1.2.3").
+		WithExecutable("reginald-example").
+		WithCapabilities(api.CapabilityFSWrite).
+		AddConfig(limit, owner).
+		AddCommand(list).
+		AddTask(api.Task{Type: "link"})
+
+	got, err := b.Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := api.Manifest{
+		Name:         "Example",
+		Domain:       "example",
+		Description:  "Manage examples.",
+		Version:      "1.2.3",
+		Executable:   "reginald-example",
+		Config:       []api.ConfigEntry{limit, owner},
+		Commands:     []api.Command{list},
+		Tasks:        []api.Task{{Type: "link"}},
+		Capabilities: []string{api.CapabilityFSWrite},
+	}
+
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %+v, want %+v", got, want)
+	}
+
+	// The built manifests must not share state with the builder.
+	got.Commands[0].Aliases[0] = "changed"
+	got.Commands[0].Config[0].Flag.Shorthand = "x"
+	*got.Config[0].Min = 5
+	got.Config[0].AllowedValues[0] = 5
+	got.Config[1].Fields[0].Key = "changed"
+
+	again, err := b.AddTask(api.Task{Type: "copy"}).Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if again.Commands[0].Aliases[0] != "ls" || again.Commands[0].Config[0].Flag.Shorthand != "f" {
+		t.Errorf("builder was modified through a built manifest: %+v", again.Commands[0])
+	}
+
+	if e := again.Config[0]; *e.Min != 1 || e.AllowedValues[0] != 1 {
+		t.Errorf("builder was modified through a built manifest: %+v", e)
+	}
+
+	if e := again.Config[1]; e.Fields[0].Key != "name" {
+		t.Errorf("builder was modified through a built manifest: %+v", e)
+	}
+
+	if len(got.Tasks) != 1 || len(again.Tasks) != 2 {
+		t.Errorf("got %d and %d tasks, want 1 and 2", len(got.Tasks), len(again.Tasks))
+	}
+}
+
+func TestManifestBuilderError(t *testing.T) {
+	t.Parallel()
+
+	_, err := api.NewManifestBuilder("Example", "help").Build()
+	if want := "domain is reserved"; err == nil || !strings.Contains(err.Error(), want) {
+		t.Errorf("got %v, want string containing %q", err, want)
+	}
+
+	b := api.NewManifestBuilder("Example", "example")
+
+	_, err = b.AddTask(api.Task{Type: "link"}).AddTask(api.Task{Type: "link"}).Build()
+	if want := "duplicate task type"; err == nil || !strings.Contains(err.Error(), want) {
+		t.Errorf("got %v, want string containing %q", err, want)
+	}
+}
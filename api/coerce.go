@@ -0,0 +1,167 @@
+// Copyright 2025 Antti Kivi
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"strconv"
+)
+
+// Errors returned by the string config conversion.
+var (
+	errParseValue = errors.New("cannot parse value")
+	errUnknownKey = errors.New("unknown config key")
+)
+
+// CoerceOptions are the options for [Manifest.CoerceStringConfig]. A zero
+// CoerceOptions consists entirely of the default values.
+type CoerceOptions struct {
+	// Command is the name or alias of the command whose config is converted.
+	// If it is set, the keys are looked up in the full config of the command,
+	// as returned by [Manifest.CommandConfig], before Config so that
+	// the command-only keys are also converted.
+	Command string
+
+	// Lenient causes the keys that have no config entry in the manifest to be
+	// returned as [StringValue] KeyValues with the raw string as the value.
+	// By default, they are reported as errors.
+	Lenient bool
+}
+
+// CoerceStringConfig converts the config in which every value is a string,
+// like the config of the plugins written before the typed config, to typed
+// KeyValues. Each value is parsed with [ConfigEntry.ParseValue] of the entry
+// with the same key in Config or GlobalConfig, or in the config of
+// the command set in opts. The KeyValues are returned sorted by their keys.
+// The returned error lists every value that could not be converted, and
+// the KeyValues are returned only if all of them were converted. It returns
+// an error if the command does not exist or if its config cannot be resolved.
+// If opts is nil, the default options are used.
+func (m *Manifest) CoerceStringConfig(
+	raw map[string]string,
+	opts *CoerceOptions,
+) ([]KeyValue, error) {
+	var o CoerceOptions
+
+	if opts != nil {
+		o = *opts
+	}
+
+	scopes := [][]ConfigEntry{m.Config, m.GlobalConfig}
+
+	if o.Command != "" {
+		all, err := m.CommandConfig(o.Command)
+		if err != nil {
+			return nil, err
+		}
+
+		scopes = [][]ConfigEntry{all, m.Config}
+	}
+
+	kvs := make([]KeyValue, 0, len(raw))
+	errs := make([]error, 0, len(raw))
+
+	for _, k := range slices.Sorted(maps.Keys(raw)) {
+		i := -1
+
+		var entries []ConfigEntry
+
+		for _, entries = range scopes {
+			if i = indexConfigEntry(entries, k); i >= 0 {
+				break
+			}
+		}
+
+		if i < 0 {
+			if !o.Lenient {
+				errs = append(errs, fmt.Errorf("%w: %q", errUnknownKey, k))
+
+				continue
+			}
+
+			kvs = append(kvs, KeyValue{Key: k, Value: raw[k], Type: StringValue, Fields: nil})
+
+			continue
+		}
+
+		kv, err := entries[i].ParseValue(raw[k])
+		if err != nil {
+			errs = append(errs, err)
+
+			continue
+		}
+
+		kvs = append(kvs, kv)
+	}
+
+	if len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+
+	return kvs, nil
+}
+
+// ParseValue parses s as a value of the ConfigEntry and returns it as
+// a KeyValue with the Key, the Type, and the Fields of the entry. A [BoolValue]
+// is parsed with [strconv.ParseBool], an [IntValue] and a [UintValue] as
+// decimal integers, a [StringValue] and a [PathValue] are used as is, and
+// an [ObjectValue] is decoded from a JSON object. The value is then checked
+// and normalized with [ConfigEntry.ValidateValue]. It returns an error if s
+// cannot be parsed or if the value is not valid for the entry.
+func (e ConfigEntry) ParseValue(s string) (KeyValue, error) {
+	kv := KeyValue{Key: e.Key, Value: nil, Type: e.Type, Fields: e.Fields}
+
+	var err error
+
+	switch e.Type {
+	case BoolValue:
+		kv.Value, err = strconv.ParseBool(s)
+	case IntValue:
+		kv.Value, err = strconv.ParseInt(s, 10, 64)
+	case UintValue:
+		kv.Value, err = strconv.ParseUint(s, 10, 64)
+	case StringValue, PathValue:
+		kv.Value = s
+	case ObjectValue:
+		dec := json.NewDecoder(bytes.NewReader([]byte(s)))
+		dec.UseNumber()
+
+		err = dec.Decode(&kv.Value)
+	default:
+		return KeyValue{}, fmt.Errorf("%w: key %q: %s", errUnsupported, e.Key, e.Type)
+	}
+
+	if err != nil {
+		return KeyValue{}, fmt.Errorf(
+			"%w: key %q: %q as %s: %w",
+			errParseValue,
+			e.Key,
+			s,
+			e.Type,
+			err,
+		)
+	}
+
+	if err := e.ValidateValue(&kv); err != nil {
+		return KeyValue{}, err
+	}
+
+	return kv, nil
+}
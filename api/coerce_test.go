@@ -0,0 +1,225 @@
+// Copyright 2025 Antti Kivi
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api_test
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/reginald-project/reginald-sdk-go/api"
+)
+
+func TestConfigEntryParseValue(t *testing.T) {
+	t.Parallel()
+
+	owner := []api.KeyValue{{Key: "name", Type: api.StringValue}, {Key: "uid", Type: api.IntValue}}
+
+	for _, test := range []struct {
+		e    api.ConfigEntry
+		in   string
+		want any
+	}{
+		{api.NewConfigEntry("verbose", api.BoolValue), "true", true},
+		{api.NewConfigEntry("verbose", api.BoolValue), "0", false},
+		{api.NewConfigEntry("jobs", api.IntValue), "-4", -4},
+		{
+			api.NewConfigEntry("size", api.UintValue),
+			"18446744073709551615",
+			uint64(18446744073709551615),
+		},
+		{api.NewConfigEntry("name", api.StringValue), " spaced ", " spaced "},
+		{api.NewConfigEntry("dir", api.PathValue), "~/.config", "~/.config"},
+		{
+			api.NewConfigEntry(
+				"format",
+				api.StringValue,
+				api.WithAllowed("JSON"),
+				func(e *api.ConfigEntry) {
+					e.CaseInsensitive = true
+				},
+			),
+			"json",
+			"JSON",
+		},
+		{
+			api.NewConfigEntry("owner", api.ObjectValue, api.WithFields(owner...)),
+			`{"name":"antti","uid":1000}`,
+			map[string]any{"name": "antti", "uid": 1000},
+		},
+	} {
+		got, err := test.e.ParseValue(test.in)
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", test.in, err)
+
+			continue
+		}
+
+		want := api.KeyValue{
+			Key:    test.e.Key,
+			Value:  test.want,
+			Type:   test.e.Type,
+			Fields: test.e.Fields,
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("%s: got %#v, want %#v", test.in, got, want)
+		}
+	}
+}
+
+func TestConfigEntryParseValueError(t *testing.T) {
+	t.Parallel()
+
+	for _, test := range []struct {
+		e    api.ConfigEntry
+		in   string
+		want string
+	}{
+		{
+			api.NewConfigEntry("verbose", api.BoolValue),
+			"yes",
+			`cannot parse value: key "verbose": "yes" as bool`,
+		},
+		{
+			api.NewConfigEntry("jobs", api.IntValue),
+			"4.5",
+			`cannot parse value: key "jobs": "4.5" as int`,
+		},
+		{
+			api.NewConfigEntry("size", api.UintValue),
+			"-1",
+			`cannot parse value: key "size": "-1" as uint`,
+		},
+		{
+			api.NewConfigEntry("owner", api.ObjectValue),
+			"{",
+			`cannot parse value: key "owner": "{" as object`,
+		},
+		{api.NewConfigEntry("owner", api.ObjectValue), `"x"`, "value does not match type"},
+		{api.NewConfigEntry("jobs", api.IntValue, api.WithMax(8)), "9", "value is out of range"},
+		{api.NewConfigEntry("list", "list"), "a,b", `unsupported value type: key "list": list`},
+	} {
+		_, err := test.e.ParseValue(test.in)
+		if err == nil || !strings.Contains(err.Error(), test.want) {
+			t.Errorf("%s: got %v, want string containing %q", test.in, err, test.want)
+		}
+	}
+}
+
+func TestManifestCoerceStringConfig(t *testing.T) {
+	t.Parallel()
+
+	m := &api.Manifest{
+		Name:   "Example",
+		Domain: "example",
+		Config: []api.ConfigEntry{
+			api.NewConfigEntry("jobs", api.IntValue),
+			api.NewConfigEntry("dry-run", api.BoolValue),
+		},
+		GlobalConfig: []api.ConfigEntry{api.NewConfigEntry("color", api.BoolValue)},
+	}
+
+	got, err := m.CoerceStringConfig(
+		map[string]string{"jobs": "8", "color": "false", "dry-run": "1"},
+		nil,
+	)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := []api.KeyValue{
+		{Key: "color", Value: false, Type: api.BoolValue},
+		{Key: "dry-run", Value: true, Type: api.BoolValue},
+		{Key: "jobs", Value: 8, Type: api.IntValue},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %+v, want %+v", got, want)
+	}
+
+	raw := map[string]string{"jobs": "many", "color": "maybe", "dry-run": "true", "legacy": "x"}
+
+	_, err = m.CoerceStringConfig(raw, nil)
+	if err == nil {
+		t.Fatal("got nil error, want error")
+	}
+
+	for _, want := range []string{`key "jobs"`, `key "color"`, `unknown config key: "legacy"`} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("got %v, want string containing %q", err, want)
+		}
+	}
+
+	got, err = m.CoerceStringConfig(
+		map[string]string{"jobs": "2", "legacy": "x"},
+		&api.CoerceOptions{Lenient: true},
+	)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want = []api.KeyValue{
+		{Key: "jobs", Value: 2, Type: api.IntValue},
+		{Key: "legacy", Value: "x", Type: api.StringValue},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %+v, want %+v", got, want)
+	}
+}
+
+func TestManifestCoerceStringConfigCommand(t *testing.T) {
+	t.Parallel()
+
+	m := &api.Manifest{
+		Name:         "Example",
+		Domain:       "example",
+		Config:       []api.ConfigEntry{api.NewConfigEntry("jobs", api.IntValue)},
+		GlobalConfig: []api.ConfigEntry{api.NewConfigEntry("color", api.BoolValue)},
+		Commands: []api.Command{
+			{
+				Name:    "list",
+				Aliases: []string{"ls"},
+				Config:  []api.ConfigEntry{api.NewConfigEntry("limit", api.UintValue)},
+			},
+		},
+	}
+
+	raw := map[string]string{"color": "true", "jobs": "2", "limit": "10"}
+
+	got, err := m.CoerceStringConfig(raw, &api.CoerceOptions{Command: "ls"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := []api.KeyValue{
+		{Key: "color", Value: true, Type: api.BoolValue},
+		{Key: "jobs", Value: 2, Type: api.IntValue},
+		{Key: "limit", Value: uint64(10), Type: api.UintValue},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %+v, want %+v", got, want)
+	}
+
+	wantErr := `unknown config key: "limit"`
+	if _, err := m.CoerceStringConfig(raw, nil); err == nil ||
+		!strings.Contains(err.Error(), wantErr) {
+		t.Errorf("got %v, want string containing %q", err, wantErr)
+	}
+
+	wantErr = `command not found: "build"`
+	if _, err := m.CoerceStringConfig(raw, &api.CoerceOptions{Command: "build"}); err == nil ||
+		!strings.Contains(err.Error(), wantErr) {
+		t.Errorf("got %v, want string containing %q", err, wantErr)
+	}
+}
@@ -0,0 +1,169 @@
+// Copyright 2025 Antti Kivi
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"cmp"
+	"fmt"
+)
+
+// The hints for completing the value of a flag.
+const (
+	// HintNone means that the value of the flag has no completion.
+	HintNone CompletionHint = ""
+
+	// HintFile means that the value of the flag is completed as a file path.
+	HintFile CompletionHint = "file"
+)
+
+// CompletionHint tells how the shell completion should complete the value of
+// a flag when the flag has no fixed set of values.
+type CompletionHint string
+
+// A CompletionSpec is the data that Reginald needs for generating the shell
+// completion for the commands of a plugin. It is derived from the manifest with
+// [Manifest.CompletionSpec], so it stays in sync with the manifest, and it can
+// be encoded as JSON.
+type CompletionSpec struct {
+	// Domain is the domain of the plugin that the user writes before
+	// the commands.
+	Domain string `json:"domain"`
+
+	// Flags are the flags of the plugin-level Config and the GlobalConfig.
+	Flags []FlagCompletion `json:"flags,omitempty"`
+
+	// Commands are the commands of the plugin.
+	Commands []CommandCompletion `json:"commands,omitempty"`
+}
+
+// A CommandCompletion is the completion data of a single command.
+type CommandCompletion struct {
+	// Name is the name of the command.
+	Name string `json:"name"`
+
+	// Aliases are the aliases of the command.
+	Aliases []string `json:"aliases,omitempty"`
+
+	// Description is the description of the command.
+	Description string `json:"description,omitempty"`
+
+	// Flags are the flags of the command, including the flags of
+	// the GlobalConfig.
+	Flags []FlagCompletion `json:"flags,omitempty"`
+}
+
+// A FlagCompletion is the completion data of a single flag.
+type FlagCompletion struct {
+	// Name is the effective long name of the flag. See [ConfigEntry.FlagName].
+	Name string `json:"name"`
+
+	// Shorthand is the shorthand of the flag, if it has one.
+	Shorthand string `json:"shorthand,omitempty"`
+
+	// Inverse is the long name of the inverse flag of a boolean flag, if it has
+	// one.
+	Inverse string `json:"inverse,omitempty"`
+
+	// Description is the description of the flag.
+	Description string `json:"description,omitempty"`
+
+	// NoValue tells that the flag does not take a value as it is a boolean
+	// flag.
+	NoValue bool `json:"noValue,omitempty"`
+
+	// Values are the suggested values of the flag. They are the AllowedValues
+	// of the ConfigEntry formatted as strings.
+	Values []string `json:"values,omitempty"`
+
+	// Hint tells how to complete the value if the flag has no Values.
+	Hint CompletionHint `json:"hint,omitempty"`
+}
+
+// CompletionSpec returns the completion data of the plugin: the flags of
+// the plugin and the commands with their aliases and flags. Only the entries
+// that have a flag are included. The Hidden commands and config entries are
+// omitted as they are not shown in the help output either. The inheriting
+// entries of the commands are resolved with [Manifest.ResolveEntry]; an entry
+// that cannot be resolved is used as is, so the manifest should be validated
+// first.
+func (m *Manifest) CompletionSpec() CompletionSpec {
+	spec := CompletionSpec{
+		Domain:   m.Domain,
+		Flags:    flagCompletions(m.Config, m.GlobalConfig),
+		Commands: make([]CommandCompletion, 0, len(m.Commands)),
+	}
+
+	for _, c := range m.Commands {
+		if c.Hidden {
+			continue
+		}
+
+		config := make([]ConfigEntry, 0, len(c.Config))
+
+		for _, e := range c.Config {
+			if resolved, err := m.ResolveEntry(e); err == nil {
+				e = resolved
+			}
+
+			config = append(config, e)
+		}
+
+		spec.Commands = append(spec.Commands, CommandCompletion{
+			Name:        c.Name,
+			Aliases:     c.Aliases,
+			Description: c.Description,
+			Flags:       flagCompletions(m.GlobalConfig, config),
+		})
+	}
+
+	return spec
+}
+
+// flagCompletions returns the completion data of the flags of the given lists
+// of entries in order. The Hidden entries are skipped.
+func flagCompletions(lists ...[]ConfigEntry) []FlagCompletion {
+	var flags []FlagCompletion
+
+	for _, entries := range lists {
+		for _, e := range entries {
+			name, ok := e.FlagName()
+			if !ok || e.Hidden {
+				continue
+			}
+
+			f := FlagCompletion{
+				Name:        name,
+				Shorthand:   e.Flag.Shorthand,
+				Inverse:     e.Flag.Inverse,
+				Description: cmp.Or(e.Flag.Description, e.Description),
+				NoValue:     e.Type == BoolValue,
+				Values:      nil,
+				Hint:        HintNone,
+			}
+
+			for _, v := range e.AllowedValues {
+				f.Values = append(f.Values, fmt.Sprint(v))
+			}
+
+			if e.Type == PathValue && len(f.Values) == 0 {
+				f.Hint = HintFile
+			}
+
+			flags = append(flags, f)
+		}
+	}
+
+	return flags
+}
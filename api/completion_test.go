@@ -0,0 +1,95 @@
+// Copyright 2025 Antti Kivi
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/reginald-project/reginald-sdk-go/api"
+)
+
+func TestManifestCompletionSpec(t *testing.T) {
+	t.Parallel()
+
+	m := &api.Manifest{
+		Name:   "Example",
+		Domain: "example",
+		Config: []api.ConfigEntry{
+			{
+				KeyValue: api.KeyValue{Key: "config", Type: api.PathValue},
+				Flag:     &api.Flag{Shorthand: "c"},
+			},
+			{KeyValue: api.KeyValue{Key: "token", Type: api.StringValue}},
+			{
+				KeyValue: api.KeyValue{Key: "debug", Type: api.BoolValue},
+				Flag:     &api.Flag{},
+				Hidden:   true,
+			},
+		},
+		GlobalConfig: []api.ConfigEntry{
+			{
+				KeyValue: api.KeyValue{Key: "color", Type: api.BoolValue},
+				Flag:     &api.Flag{Inverse: "no-color"},
+			},
+		},
+		Commands: []api.Command{
+			{
+				Name:    "list",
+				Aliases: []string{"ls"},
+				Config: []api.ConfigEntry{
+					{
+						KeyValue:      api.KeyValue{Key: "format", Type: api.StringValue},
+						Flag:          &api.Flag{Description: "Output format."},
+						AllowedValues: []any{"text", "json"},
+					},
+					{KeyValue: api.KeyValue{Key: "config"}, Inherit: "config"},
+					{Inherit: "debug"},
+				},
+			},
+			{
+				Name:   "internal",
+				Config: []api.ConfigEntry{{KeyValue: api.KeyValue{Key: "x"}, Flag: &api.Flag{}}},
+				Hidden: true,
+			},
+		},
+	}
+
+	color := api.FlagCompletion{Name: "color", Inverse: "no-color", NoValue: true}
+	config := api.FlagCompletion{Name: "config", Shorthand: "c", Hint: api.HintFile}
+	want := api.CompletionSpec{
+		Domain: "example",
+		Flags:  []api.FlagCompletion{config, color},
+		Commands: []api.CommandCompletion{
+			{
+				Name:    "list",
+				Aliases: []string{"ls"},
+				Flags: []api.FlagCompletion{
+					color,
+					{
+						Name:        "format",
+						Description: "Output format.",
+						Values:      []string{"text", "json"},
+					},
+					config,
+				},
+			},
+		},
+	}
+
+	if got := m.CompletionSpec(); !reflect.DeepEqual(got, want) {
+		t.Errorf("got %+v, want %+v", got, want)
+	}
+}
@@ -0,0 +1,650 @@
+// Copyright 2025 Antti Kivi
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"maps"
+	"os"
+	"reflect"
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// Errors returned by the config entry utilities.
+var (
+	errCommandNotFound = errors.New("command not found")
+	errInheritNotFound = errors.New("inherited config entry not found")
+	errInheritType     = errors.New("inheriting config entry changes the value type")
+	errMissingFlag     = errors.New("missing required flag")
+	errMissingValue    = errors.New("missing required value")
+	errNoFileRef       = errors.New("config entry cannot be read from a file")
+	errMutexFlags      = errors.New("flags are mutually exclusive")
+	errInvalidPattern  = errors.New("invalid regular expression")
+	errNotAllowed      = errors.New("value is not allowed")
+	errOutOfRange      = errors.New("value is out of range")
+	errPatternMismatch = errors.New("value does not match pattern")
+)
+
+// patternCache holds the compiled regular expressions of the Pattern
+// constraints of a Manifest by their source. It is safe for concurrent use.
+type patternCache struct {
+	mu sync.Mutex
+	re map[string]*regexp.Regexp
+}
+
+// CheckMutex checks the given set flags against the mutually exclusive flag
+// groups of the command. The keys of setFlags are the long names of the flags
+// and a flag is considered set if its value in the map is true. It returns
+// an error naming the conflicting flags if more than one flag in a group is
+// set.
+func (c Command) CheckMutex(setFlags map[string]bool) error {
+	for _, group := range c.MutexGroups {
+		var set []string
+
+		for _, name := range group {
+			if setFlags[name] {
+				set = append(set, "--"+name)
+			}
+		}
+
+		if len(set) > 1 {
+			return fmt.Errorf("%w: %s", errMutexFlags, strings.Join(set, ", "))
+		}
+	}
+
+	return nil
+}
+
+// CommandConfig returns the full config of the command with the given name or
+// alias: the GlobalConfig of the plugin followed by the config of the command.
+// The inheriting entries of the command are resolved with
+// [Manifest.ResolveEntry]. It returns an error if the command does not exist
+// or if an entry cannot be resolved.
+func (m *Manifest) CommandConfig(name string) ([]ConfigEntry, error) {
+	c, ok := m.LookupCommand(name)
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", errCommandNotFound, name)
+	}
+
+	result := make([]ConfigEntry, 0, len(m.GlobalConfig)+len(c.Config))
+	result = append(result, m.GlobalConfig...)
+
+	for _, e := range c.Config {
+		resolved, err := m.ResolveEntry(e)
+		if err != nil {
+			return nil, fmt.Errorf("command %q: %w", c.Name, err)
+		}
+
+		result = append(result, resolved)
+	}
+
+	return result, nil
+}
+
+// Default returns the default value of the ConfigEntry as a KeyValue with
+// the value converted to the Go type that corresponds to its Type, and reports
+// whether the entry has a default value. The entry has no default value if its
+// Value is nil, and then Reginald should treat the value as unset instead of
+// using the zero value of the type. An explicit false, 0, or "" is a default
+// value. If the value cannot be converted, it is returned as is.
+func (e ConfigEntry) Default() (KeyValue, bool) {
+	if e.Value == nil {
+		return KeyValue{Key: e.Key, Value: nil, Type: e.Type, Fields: e.Fields}, false
+	}
+
+	kv := e.KeyValue
+	kv.Value = kv.normalizedValue()
+
+	return kv, true
+}
+
+// FlagName returns the effective long name of the command-line flag of
+// the ConfigEntry and reports whether the ConfigEntry has a flag at all. If
+// the Flag is nil, it returns false. If the name of the Flag is empty, the Key
+// of the ConfigEntry is used as the name of the flag.
+func (e ConfigEntry) FlagName() (string, bool) {
+	if e.Flag == nil {
+		return "", false
+	}
+
+	if e.Flag.Name != "" {
+		return e.Flag.Name, true
+	}
+
+	return e.Key, true
+}
+
+// GroupEntries returns the given entries grouped by their Group. The entries
+// without a Group are in the default group that has the empty string as its
+// key. The entries within each group are in the same order as in entries. Use
+// [GroupNames] to get the groups in the order they appear in.
+func GroupEntries(entries []ConfigEntry) map[string][]ConfigEntry {
+	groups := make(map[string][]ConfigEntry)
+
+	for _, e := range entries {
+		groups[e.Group] = append(groups[e.Group], e)
+	}
+
+	return groups
+}
+
+// GroupNames returns the names of the groups of the given entries in the order
+// of their first appearance. The default group is included as the empty
+// string if there are entries without a Group.
+func GroupNames(entries []ConfigEntry) []string {
+	var names []string
+
+	for _, e := range entries {
+		if !slices.Contains(names, e.Group) {
+			names = append(names, e.Group)
+		}
+	}
+
+	return names
+}
+
+// CheckRequires checks the given set flags against the flag dependencies of
+// the command. The keys of setFlags are the long names of the flags and a flag
+// is considered set if its value in the map is true. It returns an error
+// naming the flag and its missing dependency if a flag is set without a flag
+// it requires. The flags are checked in sorted order so that the error is
+// deterministic.
+func (c Command) CheckRequires(setFlags map[string]bool) error {
+	for _, name := range slices.Sorted(maps.Keys(c.Requires)) {
+		if !setFlags[name] {
+			continue
+		}
+
+		for _, required := range c.Requires[name] {
+			if !setFlags[required] {
+				return fmt.Errorf("%w: --%s requires --%s", errMissingFlag, name, required)
+			}
+		}
+	}
+
+	return nil
+}
+
+// MissingError returns the error to show to the user when the value of
+// the required ConfigEntry is missing. If RequiredMessage is set, the message
+// of the error is RequiredMessage as is. Otherwise, the message names the key
+// and the flag and the EnvOverride of the entry if they are set. The default
+// environment variable name depends on the plugin and the command, so use
+// [Manifest.MissingError] to name the variable for every entry.
+func (e ConfigEntry) MissingError() error {
+	env := ""
+	if e.EnvOverride != "" && !e.FlagOnly {
+		env = EnvNamePrefix + e.EnvOverride
+	}
+
+	return e.missingError(env)
+}
+
+// MissingError returns the error to show to the user when the value of
+// the required ConfigEntry e of the given command is missing. The command is
+// the empty string for the plugin-level config. It is like
+// [ConfigEntry.MissingError] but the message also names the environment
+// variable of the entry as returned by [Manifest.EnvName], for example,
+// REGINALD_MYPLUGIN_BUILD_TARGET. An inheriting entry is resolved with
+// [Manifest.ResolveEntry] first.
+func (m *Manifest) MissingError(command string, e ConfigEntry) error {
+	if resolved, err := m.ResolveEntry(e); err == nil {
+		e = resolved
+	}
+
+	return e.missingError(m.EnvName(command, e))
+}
+
+// missingError returns the error for the missing value of e. The env is
+// the name of the environment variable of e or the empty string if e is not
+// read from the environment.
+func (e ConfigEntry) missingError(env string) error {
+	if e.RequiredMessage != "" {
+		return errors.New(e.RequiredMessage) //nolint:err113 // the message is given by the plugin
+	}
+
+	var sources []string
+
+	if name, ok := e.FlagName(); ok && name != "" {
+		sources = append(sources, "--"+name)
+	}
+
+	if env != "" {
+		sources = append(sources, env)
+	}
+
+	if len(sources) == 0 {
+		return fmt.Errorf("%w: key %q", errMissingValue, e.Key)
+	}
+
+	return fmt.Errorf(
+		"%w: key %q, set it with %s",
+		errMissingValue,
+		e.Key,
+		strings.Join(sources, " or "),
+	)
+}
+
+// ResolveFromFile returns the value of the ConfigEntry read from the file at
+// path as a KeyValue with the Key and the Type of the entry. A single trailing
+// newline is removed from the contents of the file. The value is checked with
+// [ConfigEntry.ValidateValue]. It returns an error if the entry does not have
+// FileRef set, if the file cannot be read, or if the value is not valid.
+func (e ConfigEntry) ResolveFromFile(path string) (KeyValue, error) {
+	if !e.FileRef {
+		return KeyValue{}, fmt.Errorf("%w: key %q", errNoFileRef, e.Key)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return KeyValue{}, fmt.Errorf("failed to read the value of key %q: %w", e.Key, err)
+	}
+
+	s := strings.TrimSuffix(string(data), "\n")
+	s = strings.TrimSuffix(s, "\r")
+
+	kv := KeyValue{Key: e.Key, Value: s, Type: e.Type, Fields: nil}
+	if err := e.ValidateValue(&kv); err != nil {
+		return KeyValue{}, fmt.Errorf("value of key %q in %s: %w", e.Key, path, err)
+	}
+
+	return kv, nil
+}
+
+// RequiredConfig returns the config entries that the user must set before
+// the plugin can be used: the entries that are Required and have no default
+// value as reported by [ConfigEntry.Default]. The entries of Config and
+// GlobalConfig come first, followed by the entries of the commands in order.
+// The inheriting entries of the commands are resolved with
+// [Manifest.ResolveEntry] first; an entry that cannot be resolved is used as
+// is. The task config is not included.
+func (m *Manifest) RequiredConfig() []ConfigEntry {
+	var result []ConfigEntry
+
+	add := func(e ConfigEntry) {
+		if _, ok := e.Default(); e.Required && !ok {
+			result = append(result, e)
+		}
+	}
+
+	for _, e := range m.Config {
+		add(e)
+	}
+
+	for _, e := range m.GlobalConfig {
+		add(e)
+	}
+
+	for _, c := range m.Commands {
+		for _, e := range c.Config {
+			if resolved, err := m.ResolveEntry(e); err == nil {
+				e = resolved
+			}
+
+			add(e)
+		}
+	}
+
+	return result
+}
+
+// ResolveEntry returns the ConfigEntry that results from merging e onto
+// the plugin-level ConfigEntry named by e.Inherit. If e does not inherit
+// an entry, it is returned as is. Every field of e that is set overrides
+// the field of the inherited entry as follows:
+//
+//   - Key, Type, Description, EnvOverride, Deprecated, Fields, Order, Group,
+//     Pattern, and RequiredMessage are taken from e if they are set and otherwise from
+//     the inherited entry.
+//   - Value, AllowedValues, Min, and Max are taken from e if they are not nil
+//     and otherwise from the inherited entry.
+//   - If both entries have a Flag, the non-empty fields of the Flag in
+//     e override the fields of the inherited Flag. Otherwise, the Flag that is
+//     set is used.
+//   - FlagOnly, Sensitive, Hidden, CaseInsensitive, Required, and FileRef are
+//     true if they are set in either of the entries.
+//
+// It returns an error if the inherited entry does not exist or if e sets
+// a Type that is different from the Type of the inherited entry.
+func (m *Manifest) ResolveEntry(e ConfigEntry) (ConfigEntry, error) {
+	if e.Inherit == "" {
+		return e, nil
+	}
+
+	i := indexConfigEntry(m.Config, e.Inherit)
+	if i < 0 {
+		return ConfigEntry{}, fmt.Errorf("%w: %q", errInheritNotFound, e.Inherit)
+	}
+
+	base := m.Config[i]
+
+	if e.Type != "" && e.Type != base.Type {
+		return ConfigEntry{}, fmt.Errorf(
+			"%w: %q has type %s, inherited %q has type %s",
+			errInheritType,
+			e.Key,
+			e.Type,
+			base.Key,
+			base.Type,
+		)
+	}
+
+	result := base
+	result.Inherit = ""
+	result.Key = cmp.Or(e.Key, base.Key)
+	result.Description = cmp.Or(e.Description, base.Description)
+	result.EnvOverride = cmp.Or(e.EnvOverride, base.EnvOverride)
+	result.Deprecated = cmp.Or(e.Deprecated, base.Deprecated)
+	result.Order = cmp.Or(e.Order, base.Order)
+	result.Group = cmp.Or(e.Group, base.Group)
+	result.Pattern = cmp.Or(e.Pattern, base.Pattern)
+	result.RequiredMessage = cmp.Or(e.RequiredMessage, base.RequiredMessage)
+	result.Min = cmp.Or(e.Min, base.Min)
+	result.Max = cmp.Or(e.Max, base.Max)
+
+	if e.Value != nil {
+		result.Value = e.Value
+	}
+
+	if e.Fields != nil {
+		result.Fields = e.Fields
+	}
+
+	if e.AllowedValues != nil {
+		result.AllowedValues = e.AllowedValues
+	}
+
+	result.FlagOnly = e.FlagOnly || base.FlagOnly
+	result.Sensitive = e.Sensitive || base.Sensitive
+	result.Hidden = e.Hidden || base.Hidden
+	result.CaseInsensitive = e.CaseInsensitive || base.CaseInsensitive
+	result.Required = e.Required || base.Required
+	result.FileRef = e.FileRef || base.FileRef
+	result.Flag = mergeFlags(base.Flag, e.Flag)
+
+	return result, nil
+}
+
+// ValidateValue checks that the value of kv is valid for the ConfigEntry and
+// normalizes it. The value must match the Type of the ConfigEntry and satisfy
+// the constraints of the ConfigEntry: Min and Max for numeric values, Pattern
+// for string values, and AllowedValues. On success, the value of kv is
+// replaced with its normalized form: it is converted to the Go type that
+// corresponds to the Type and, if CaseInsensitive is set, a string value is
+// replaced with the matching spelling in AllowedValues. A nil value is
+// considered unset and it is always valid; use Required to tell that a value
+// must be set. The Pattern is compiled on every call; use
+// [Manifest.ValidateValue] to check many values of a manifest.
+func (e ConfigEntry) ValidateValue(kv *KeyValue) error {
+	return e.validateValue(kv, nil)
+}
+
+// ValidateValue checks and normalizes the value of kv for the ConfigEntry e as
+// [ConfigEntry.ValidateValue] does, but the Pattern of e is compiled only once
+// and kept with the manifest for the later calls. It is safe for concurrent
+// use.
+func (m *Manifest) ValidateValue(e ConfigEntry, kv *KeyValue) error {
+	return e.validateValue(kv, m)
+}
+
+// SortEntries returns a copy of entries sorted for the help output. The entries
+// with a non-zero Order come first, sorted by Order and then by Key. They are
+// followed by the entries with no Order in their original order. The given
+// slice is not modified.
+func SortEntries(entries []ConfigEntry) []ConfigEntry {
+	result := slices.Clone(entries)
+
+	slices.SortStableFunc(result, func(a, b ConfigEntry) int {
+		switch {
+		case a.Order == 0 && b.Order == 0:
+			return 0
+		case a.Order == 0:
+			return 1
+		case b.Order == 0:
+			return -1
+		}
+
+		return cmp.Or(cmp.Compare(a.Order, b.Order), strings.Compare(a.Key, b.Key))
+	})
+
+	return result
+}
+
+// checkRange checks that the numeric value v is within Min and Max.
+func (e ConfigEntry) checkRange(v any) error {
+	n, ok := numericValue(v)
+	if !ok {
+		return nil
+	}
+
+	if e.Min != nil && n < *e.Min {
+		return fmt.Errorf("%w: key %q: %v is less than %v", errOutOfRange, e.Key, v, *e.Min)
+	}
+
+	if e.Max != nil && n > *e.Max {
+		return fmt.Errorf("%w: key %q: %v is greater than %v", errOutOfRange, e.Key, v, *e.Max)
+	}
+
+	return nil
+}
+
+// compilePattern returns the compiled Pattern of e. The compiled regular
+// expressions are cached in the manifest so that each pattern is compiled only
+// once. If m is nil, the pattern is compiled without caching it.
+func (m *Manifest) compilePattern(e ConfigEntry) (*regexp.Regexp, error) {
+	if m == nil {
+		return compilePattern(e)
+	}
+
+	c, _ := m.patterns.Load().(*patternCache)
+	if c == nil {
+		// If another goroutine stores its cache first, the swap fails and
+		// that cache is used.
+		m.patterns.CompareAndSwap(
+			nil,
+			&patternCache{mu: sync.Mutex{}, re: make(map[string]*regexp.Regexp)},
+		)
+
+		c, _ = m.patterns.Load().(*patternCache)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if re, ok := c.re[e.Pattern]; ok {
+		return re, nil
+	}
+
+	re, err := compilePattern(e)
+	if err != nil {
+		return nil, err
+	}
+
+	c.re[e.Pattern] = re
+
+	return re, nil
+}
+
+// validateValue implements ValidateValue. The patterns are compiled with
+// m.compilePattern.
+func (e ConfigEntry) validateValue(kv *KeyValue, m *Manifest) error {
+	v, err := coerceValue(e.Key, e.Type, e.Fields, kv.Value)
+	if err != nil {
+		return err
+	}
+
+	if v == nil {
+		kv.Value = nil
+
+		return nil
+	}
+
+	if err := e.checkRange(v); err != nil {
+		return err
+	}
+
+	if err := e.checkPattern(v, m); err != nil {
+		return err
+	}
+
+	if len(e.AllowedValues) > 0 {
+		v, err = e.matchAllowed(v)
+		if err != nil {
+			return err
+		}
+	}
+
+	kv.Value = v
+
+	return nil
+}
+
+// checkPattern checks that the string value v matches Pattern.
+func (e ConfigEntry) checkPattern(v any, m *Manifest) error {
+	s, ok := v.(string)
+	if !ok || e.Pattern == "" {
+		return nil
+	}
+
+	re, err := m.compilePattern(e)
+	if err != nil {
+		return err
+	}
+
+	if !re.MatchString(s) {
+		return fmt.Errorf(
+			"%w: key %q: %q does not match %q",
+			errPatternMismatch,
+			e.Key,
+			s,
+			e.Pattern,
+		)
+	}
+
+	return nil
+}
+
+// matchAllowed returns the allowed value that v matches.
+func (e ConfigEntry) matchAllowed(v any) (any, error) {
+	for _, allowed := range e.AllowedValues {
+		a, err := coerceValue(e.Key, e.Type, e.Fields, allowed)
+		if err != nil {
+			return nil, err
+		}
+
+		if reflect.DeepEqual(a, v) {
+			return a, nil
+		}
+
+		if e.CaseInsensitive {
+			as, aok := a.(string)
+			vs, vok := v.(string)
+
+			if aok && vok && strings.EqualFold(as, vs) {
+				return as, nil
+			}
+		}
+	}
+
+	return nil, fmt.Errorf(
+		"%w: key %q: %v, want one of %v",
+		errNotAllowed,
+		e.Key,
+		v,
+		e.AllowedValues,
+	)
+}
+
+// compilePattern compiles the Pattern of e.
+func compilePattern(e ConfigEntry) (*regexp.Regexp, error) {
+	re, err := regexp.Compile(e.Pattern)
+	if err != nil {
+		return nil, fmt.Errorf("%w: key %q: %w", errInvalidPattern, e.Key, err)
+	}
+
+	return re, nil
+}
+
+// numericValue returns the numeric value v as float64 and reports whether v is
+// numeric.
+func numericValue(v any) (float64, bool) {
+	switch n := v.(type) {
+	case int:
+		return float64(n), true
+	case int64:
+		return float64(n), true
+	case uint64:
+		return float64(n), true
+	case float64:
+		return n, true
+	default:
+		return 0, false
+	}
+}
+
+// indexConfigEntry returns the index of the ConfigEntry with the given key in
+// entries or -1 if there is no such ConfigEntry.
+func indexConfigEntry(entries []ConfigEntry, key string) int {
+	for i, e := range entries {
+		if e.Key == key {
+			return i
+		}
+	}
+
+	return -1
+}
+
+// mergeFlags returns a Flag with the non-empty fields of override set on top of
+// the fields of base. It returns nil if both of the Flags are nil.
+func mergeFlags(base, override *Flag) *Flag {
+	if base == nil && override == nil {
+		return nil
+	}
+
+	if base == nil {
+		f := *override
+
+		return &f
+	}
+
+	f := *base
+
+	if override == nil {
+		return &f
+	}
+
+	if override.Name != "" {
+		f.Name = override.Name
+	}
+
+	if override.Shorthand != "" {
+		f.Shorthand = override.Shorthand
+	}
+
+	if override.Description != "" {
+		f.Description = override.Description
+	}
+
+	if override.Inverse != "" {
+		f.Inverse = override.Inverse
+	}
+
+	return &f
+}
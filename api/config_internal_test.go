@@ -0,0 +1,91 @@
+// Copyright 2025 Antti Kivi
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+)
+
+//nolint:exhaustruct // don't care about this in tests
+func TestManifestCompilePattern(t *testing.T) {
+	t.Parallel()
+
+	e := ConfigEntry{
+		KeyValue: KeyValue{Key: "name", Type: StringValue},
+		Pattern:  `^cached-[a-z]+\d*$`,
+	}
+	m := &Manifest{Domain: "example", Config: []ConfigEntry{e}}
+
+	var wg sync.WaitGroup
+
+	for range 10 {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			if err := m.Validate(); err != nil {
+				t.Errorf("Validate: %v", err)
+			}
+		}()
+	}
+
+	wg.Wait()
+
+	first, err := m.compilePattern(e)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for i := range 100 {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			kv := KeyValue{Key: "name", Value: "cached-abc" + strconv.Itoa(i), Type: StringValue}
+			if err := m.ValidateValue(e, &kv); err != nil {
+				t.Errorf("ValidateValue(%v): %v", kv.Value, err)
+			}
+
+			if re, _ := m.compilePattern(e); re != first {
+				t.Error("pattern was compiled again")
+			}
+		}()
+	}
+
+	wg.Wait()
+
+	other := &Manifest{Domain: "example", Config: []ConfigEntry{e}}
+	if err := other.Validate(); err != nil {
+		t.Fatal(err)
+	}
+
+	c, _ := other.patterns.Load().(*patternCache)
+	if c == nil || c == m.patterns.Load() {
+		t.Error("manifests do not have their own pattern caches")
+	}
+
+	bad := ConfigEntry{KeyValue: KeyValue{Key: "bad", Type: StringValue}, Pattern: `[`}
+	m = &Manifest{Domain: "example", Config: []ConfigEntry{bad}}
+
+	err = m.Validate()
+	if err == nil || !strings.Contains(err.Error(), `invalid regular expression: key "bad"`) {
+		t.Errorf("got %v, want string containing %q", err, `invalid regular expression: key "bad"`)
+	}
+}
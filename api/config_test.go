@@ -0,0 +1,975 @@
+// Copyright 2025 Antti Kivi
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api_test
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/reginald-project/reginald-sdk-go/api"
+)
+
+func inheritManifest(command ...api.ConfigEntry) *api.Manifest {
+	return &api.Manifest{
+		Name:   "Example",
+		Domain: "example",
+		Config: []api.ConfigEntry{
+			{
+				KeyValue: api.KeyValue{Key: "verbose", Type: api.BoolValue, Value: false},
+				Flag: &api.Flag{
+					Name:        "verbose",
+					Shorthand:   "v",
+					Description: "Verbose output.",
+				},
+				EnvOverride: "VERBOSE",
+			},
+		},
+		Commands: []api.Command{{Name: "build", Config: command}},
+	}
+}
+
+func TestManifestResolveEntry(t *testing.T) {
+	t.Parallel()
+
+	m := inheritManifest()
+
+	got, err := m.ResolveEntry(api.ConfigEntry{
+		KeyValue: api.KeyValue{Value: true},
+		Flag:     &api.Flag{Shorthand: "V"},
+		Inherit:  "verbose",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := api.ConfigEntry{
+		KeyValue:    api.KeyValue{Key: "verbose", Type: api.BoolValue, Value: true},
+		Flag:        &api.Flag{Name: "verbose", Shorthand: "V", Description: "Verbose output."},
+		EnvOverride: "VERBOSE",
+	}
+
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %#v, want %#v", got, want)
+	}
+
+	if m.Config[0].Flag.Shorthand != "v" {
+		t.Errorf("inherited entry was modified: %#v", m.Config[0].Flag)
+	}
+
+	plain := api.ConfigEntry{KeyValue: api.KeyValue{Key: "a", Type: api.IntValue, Value: 1}}
+
+	got, err = m.ResolveEntry(plain)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !reflect.DeepEqual(got, plain) {
+		t.Errorf("got %#v, want %#v", got, plain)
+	}
+}
+
+func TestManifestResolveEntryFields(t *testing.T) {
+	t.Parallel()
+
+	m := inheritManifest()
+	limit := 3.0
+
+	//nolint:govet // don't care about this in tests
+	for _, test := range []struct {
+		name     string
+		override func(e *api.ConfigEntry)
+		want     func(e *api.ConfigEntry) // nil if the same as override
+	}{
+		{"Key", func(e *api.ConfigEntry) { e.Key = "loud" }, nil},
+		{"Description", func(e *api.ConfigEntry) { e.Description = "Loud." }, nil},
+		{"Value", func(e *api.ConfigEntry) { e.Value = true }, nil},
+		{"Fields", func(e *api.ConfigEntry) { e.Fields = []api.KeyValue{{Key: "a"}} }, nil},
+		{"EnvOverride", func(e *api.ConfigEntry) { e.EnvOverride = "LOUD" }, nil},
+		{"FlagOnly", func(e *api.ConfigEntry) { e.FlagOnly = true }, nil},
+		{"Sensitive", func(e *api.ConfigEntry) { e.Sensitive = true }, nil},
+		{"Hidden", func(e *api.ConfigEntry) { e.Hidden = true }, nil},
+		{"Deprecated", func(e *api.ConfigEntry) { e.Deprecated = "Use --loud." }, nil},
+		{"AllowedValues", func(e *api.ConfigEntry) { e.AllowedValues = []any{true} }, nil},
+		{"CaseInsensitive", func(e *api.ConfigEntry) { e.CaseInsensitive = true }, nil},
+		{"Order", func(e *api.ConfigEntry) { e.Order = 2 }, nil},
+		{"Group", func(e *api.ConfigEntry) { e.Group = "Output" }, nil},
+		{"Required", func(e *api.ConfigEntry) { e.Required = true }, nil},
+		{"Min", func(e *api.ConfigEntry) { e.Min = &limit }, nil},
+		{"Max", func(e *api.ConfigEntry) { e.Max = &limit }, nil},
+		{"Pattern", func(e *api.ConfigEntry) { e.Pattern = "^a" }, nil},
+		{"RequiredMessage", func(e *api.ConfigEntry) { e.RequiredMessage = "Set it." }, nil},
+		{"FileRef", func(e *api.ConfigEntry) { e.FileRef = true }, nil},
+		{
+			"Flag.Name",
+			func(e *api.ConfigEntry) { e.Flag = &api.Flag{Name: "loud"} },
+			func(e *api.ConfigEntry) { e.Flag.Name = "loud" },
+		},
+		{
+			"Flag.Shorthand",
+			func(e *api.ConfigEntry) { e.Flag = &api.Flag{Shorthand: "l"} },
+			func(e *api.ConfigEntry) { e.Flag.Shorthand = "l" },
+		},
+		{
+			"Flag.Description",
+			func(e *api.ConfigEntry) { e.Flag = &api.Flag{Description: "Loud."} },
+			func(e *api.ConfigEntry) { e.Flag.Description = "Loud." },
+		},
+		{
+			"Flag.Inverse",
+			func(e *api.ConfigEntry) { e.Flag = &api.Flag{Inverse: "quiet"} },
+			func(e *api.ConfigEntry) { e.Flag.Inverse = "quiet" },
+		},
+	} {
+		entry := api.ConfigEntry{Inherit: "verbose"}
+		test.override(&entry)
+
+		got, err := m.ResolveEntry(entry)
+		if err != nil {
+			t.Fatalf("%s: %v", test.name, err)
+		}
+
+		want := m.Config[0]
+		flag := *want.Flag
+		want.Flag = &flag
+
+		if test.want != nil {
+			test.want(&want)
+		} else {
+			test.override(&want)
+		}
+
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("%s: got %#v, want %#v", test.name, got, want)
+		}
+	}
+}
+
+func TestManifestValidateInherit(t *testing.T) {
+	t.Parallel()
+
+	for _, test := range []struct {
+		entry api.ConfigEntry
+		want  string // error string should contain this, empty for no error
+	}{
+		{api.ConfigEntry{Inherit: "verbose"}, ""},
+		{
+			api.ConfigEntry{
+				KeyValue: api.KeyValue{Type: api.BoolValue, Value: true},
+				Inherit:  "verbose",
+			},
+			"",
+		},
+		{api.ConfigEntry{Inherit: "missing"}, "not found"},
+		{
+			api.ConfigEntry{KeyValue: api.KeyValue{Type: api.StringValue}, Inherit: "verbose"},
+			"changes the value type",
+		},
+		{
+			api.ConfigEntry{KeyValue: api.KeyValue{Value: "yes"}, Inherit: "verbose"},
+			"does not match",
+		},
+	} {
+		err := inheritManifest(test.entry).Validate()
+		if test.want == "" {
+			if err != nil {
+				t.Errorf("%#v: unexpected error: %v", test.entry, err)
+			}
+
+			continue
+		}
+
+		if err == nil || !strings.Contains(err.Error(), test.want) {
+			t.Errorf("%#v: got %v, want string containing %q", test.entry, err, test.want)
+		}
+	}
+
+	m := inheritManifest()
+	m.Config = append(m.Config, api.ConfigEntry{Inherit: "verbose"})
+
+	if err := m.Validate(); err == nil || !strings.Contains(err.Error(), "only command") {
+		t.Errorf("got %v, want error for plugin-level inheritance", err)
+	}
+}
+
+func TestConfigEntryValidateValue(t *testing.T) {
+	t.Parallel()
+
+	entry := api.ConfigEntry{
+		KeyValue:        api.KeyValue{Key: "level", Type: api.StringValue, Value: "info"},
+		AllowedValues:   []any{"debug", "info", "warn"},
+		CaseInsensitive: true,
+	}
+
+	for _, test := range []struct {
+		in   any
+		want any
+	}{
+		{"debug", "debug"},
+		{"Debug", "debug"},
+		{"WARN", "warn"},
+		{nil, nil},
+	} {
+		kv := api.KeyValue{Key: "level", Type: api.StringValue, Value: test.in}
+		if err := entry.ValidateValue(&kv); err != nil {
+			t.Fatalf("%v: %v", test.in, err)
+		}
+
+		if kv.Value != test.want {
+			t.Errorf("%v: got %v, want %v", test.in, kv.Value, test.want)
+		}
+	}
+
+	kv := api.KeyValue{Key: "level", Type: api.StringValue, Value: "trace"}
+
+	err := entry.ValidateValue(&kv)
+	if err == nil || !strings.Contains(err.Error(), "not allowed") {
+		t.Errorf("got %v, want not allowed error", err)
+	}
+
+	entry.CaseInsensitive = false
+
+	kv = api.KeyValue{Key: "level", Type: api.StringValue, Value: "Debug"}
+	if err := entry.ValidateValue(&kv); err == nil {
+		t.Error("expected an error for a case mismatch without CaseInsensitive")
+	}
+
+	ints := api.ConfigEntry{
+		KeyValue:      api.KeyValue{Key: "n", Type: api.IntValue},
+		AllowedValues: []any{1, 2},
+	}
+
+	kv = api.KeyValue{Key: "n", Type: api.IntValue, Value: 2.0}
+	if err := ints.ValidateValue(&kv); err != nil {
+		t.Fatal(err)
+	}
+
+	if kv.Value != 2 {
+		t.Errorf("got %#v, want 2", kv.Value)
+	}
+}
+
+func TestManifestValidateAllowedValues(t *testing.T) {
+	t.Parallel()
+
+	for _, test := range []struct {
+		entry api.ConfigEntry
+		want  string // error string should contain this, empty for no error
+	}{
+		{
+			api.ConfigEntry{
+				KeyValue:        api.KeyValue{Key: "level", Type: api.StringValue},
+				AllowedValues:   []any{"debug", "info"},
+				CaseInsensitive: true,
+			},
+			"",
+		},
+		{
+			api.ConfigEntry{
+				KeyValue:        api.KeyValue{Key: "level", Type: api.StringValue},
+				AllowedValues:   []any{"debug", "info", "Debug"},
+				CaseInsensitive: true,
+			},
+			"differ only by case",
+		},
+		{
+			api.ConfigEntry{
+				KeyValue:      api.KeyValue{Key: "level", Type: api.StringValue},
+				AllowedValues: []any{"debug", "Debug"},
+			},
+			"",
+		},
+		{
+			api.ConfigEntry{
+				KeyValue:      api.KeyValue{Key: "level", Type: api.StringValue},
+				AllowedValues: []any{"debug", 1},
+			},
+			"allowedValues[1]",
+		},
+		{
+			api.ConfigEntry{
+				KeyValue:        api.KeyValue{Key: "n", Type: api.IntValue},
+				AllowedValues:   []any{1, 2},
+				CaseInsensitive: true,
+			},
+			"requires a string",
+		},
+	} {
+		m := &api.Manifest{
+			Name:   "Example",
+			Domain: "example",
+			Config: []api.ConfigEntry{test.entry},
+		}
+
+		err := m.Validate()
+		if test.want == "" {
+			if err != nil {
+				t.Errorf("%#v: unexpected error: %v", test.entry, err)
+			}
+
+			continue
+		}
+
+		if err == nil || !strings.Contains(err.Error(), test.want) {
+			t.Errorf("%#v: got %v, want string containing %q", test.entry, err, test.want)
+		}
+	}
+}
+
+func TestCommandCheckMutex(t *testing.T) {
+	t.Parallel()
+
+	c := api.Command{Name: "show", MutexGroups: [][]string{{"json", "yaml"}}}
+
+	for _, test := range []struct {
+		set  map[string]bool
+		want bool // whether an error is expected
+	}{
+		{nil, false},
+		{map[string]bool{"json": true}, false},
+		{map[string]bool{"json": true, "yaml": false}, false},
+		{map[string]bool{"json": true, "other": true}, false},
+		{map[string]bool{"json": true, "yaml": true}, true},
+	} {
+		err := c.CheckMutex(test.set)
+		if (err != nil) != test.want {
+			t.Errorf("%v: got %v, want error: %t", test.set, err, test.want)
+		}
+
+		if err != nil && !strings.Contains(err.Error(), "--json, --yaml") {
+			t.Errorf("%v: got %v, want error naming the flags", test.set, err)
+		}
+	}
+}
+
+func TestManifestValidateMutexGroups(t *testing.T) {
+	t.Parallel()
+
+	config := []api.ConfigEntry{
+		{KeyValue: api.KeyValue{Key: "json", Type: api.BoolValue}, Flag: &api.Flag{}},
+		{
+			KeyValue: api.KeyValue{Key: "yaml-output", Type: api.BoolValue},
+			Flag:     &api.Flag{Name: "yaml"},
+		},
+		{KeyValue: api.KeyValue{Key: "noflag", Type: api.BoolValue}},
+	}
+
+	for _, test := range []struct {
+		groups [][]string
+		want   string // error string should contain this, empty for no error
+	}{
+		{[][]string{{"json", "yaml"}}, ""},
+		{[][]string{{"json", "yaml-output"}}, "unknown flag"},
+		{[][]string{{"json", "noflag"}}, "unknown flag"},
+	} {
+		m := &api.Manifest{
+			Name:     "Example",
+			Domain:   "example",
+			Commands: []api.Command{{Name: "show", Config: config, MutexGroups: test.groups}},
+		}
+
+		err := m.Validate()
+		if test.want == "" {
+			if err != nil {
+				t.Errorf("%v: unexpected error: %v", test.groups, err)
+			}
+
+			continue
+		}
+
+		if err == nil || !strings.Contains(err.Error(), test.want) {
+			t.Errorf("%v: got %v, want string containing %q", test.groups, err, test.want)
+		}
+	}
+}
+
+func TestSortEntries(t *testing.T) {
+	t.Parallel()
+
+	entry := func(key string, order int) api.ConfigEntry {
+		return api.ConfigEntry{
+			KeyValue: api.KeyValue{Key: key, Type: api.StringValue},
+			Order:    order,
+		}
+	}
+
+	in := []api.ConfigEntry{
+		entry("zeta", 0),
+		entry("beta", 2),
+		entry("alpha", 0),
+		entry("gamma", 1),
+		entry("delta", 2),
+		entry("epsilon", 0),
+		entry("first", -1),
+	}
+
+	got := make([]string, 0, len(in))
+	for _, e := range api.SortEntries(in) {
+		got = append(got, e.Key)
+	}
+
+	want := []string{"first", "gamma", "beta", "delta", "zeta", "alpha", "epsilon"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+
+	if in[0].Key != "zeta" || in[1].Key != "beta" {
+		t.Errorf("input was modified: %v", in)
+	}
+}
+
+func TestGroupEntries(t *testing.T) {
+	t.Parallel()
+
+	entry := func(key, group string) api.ConfigEntry {
+		return api.ConfigEntry{
+			KeyValue: api.KeyValue{Key: key, Type: api.StringValue},
+			Group:    group,
+		}
+	}
+
+	in := []api.ConfigEntry{
+		entry("host", "Network"),
+		entry("verbose", ""),
+		entry("format", "Output"),
+		entry("port", "Network"),
+		entry("color", "Output"),
+		entry("debug", ""),
+	}
+
+	keys := func(entries []api.ConfigEntry) []string {
+		result := make([]string, 0, len(entries))
+		for _, e := range entries {
+			result = append(result, e.Key)
+		}
+
+		return result
+	}
+
+	got := api.GroupEntries(in)
+	if len(got) != 3 {
+		t.Errorf("got %d groups, want 3", len(got))
+	}
+
+	for group, want := range map[string][]string{
+		"Network": {"host", "port"},
+		"Output":  {"format", "color"},
+		"":        {"verbose", "debug"},
+	} {
+		if k := keys(got[group]); !reflect.DeepEqual(k, want) {
+			t.Errorf("group %q: got %v, want %v", group, k, want)
+		}
+	}
+
+	names, want := api.GroupNames(in), []string{"Network", "", "Output"}
+	if !reflect.DeepEqual(names, want) {
+		t.Errorf("got %q, want %q", names, want)
+	}
+}
+
+func TestCommandCheckRequires(t *testing.T) {
+	t.Parallel()
+
+	c := api.Command{Name: "build", Requires: map[string][]string{"output-dir": {"split"}}}
+
+	for _, test := range []struct {
+		set  map[string]bool
+		want string // error string should contain this, empty for no error
+	}{
+		{nil, ""},
+		{map[string]bool{"split": true}, ""},
+		{map[string]bool{"split": true, "output-dir": true}, ""},
+		{map[string]bool{"output-dir": false}, ""},
+		{map[string]bool{"output-dir": true}, "--output-dir requires --split"},
+		{map[string]bool{"output-dir": true, "split": false}, "--output-dir requires --split"},
+	} {
+		err := c.CheckRequires(test.set)
+		if test.want == "" {
+			if err != nil {
+				t.Errorf("%v: unexpected error: %v", test.set, err)
+			}
+
+			continue
+		}
+
+		if err == nil || !strings.Contains(err.Error(), test.want) {
+			t.Errorf("%v: got %v, want string containing %q", test.set, err, test.want)
+		}
+	}
+}
+
+func TestManifestValidateRequires(t *testing.T) {
+	t.Parallel()
+
+	config := []api.ConfigEntry{
+		{KeyValue: api.KeyValue{Key: "split", Type: api.BoolValue}, Flag: &api.Flag{}},
+		{KeyValue: api.KeyValue{Key: "output-dir", Type: api.StringValue}, Flag: &api.Flag{}},
+	}
+
+	for _, test := range []struct {
+		requires map[string][]string
+		want     string // error string should contain this, empty for no error
+	}{
+		{map[string][]string{"output-dir": {"split"}}, ""},
+		{map[string][]string{"output": {"split"}}, "unknown flag"},
+		{map[string][]string{"output-dir": {"splits"}}, "unknown flag"},
+	} {
+		m := &api.Manifest{
+			Name:     "Example",
+			Domain:   "example",
+			Commands: []api.Command{{Name: "build", Config: config, Requires: test.requires}},
+		}
+
+		err := m.Validate()
+		if test.want == "" {
+			if err != nil {
+				t.Errorf("%v: unexpected error: %v", test.requires, err)
+			}
+
+			continue
+		}
+
+		if err == nil || !strings.Contains(err.Error(), test.want) {
+			t.Errorf("%v: got %v, want string containing %q", test.requires, err, test.want)
+		}
+	}
+}
+
+func TestConfigEntryFlagName(t *testing.T) {
+	t.Parallel()
+
+	for _, test := range []struct {
+		flag   *api.Flag
+		want   string
+		wantOK bool
+	}{
+		{nil, "", false},
+		{&api.Flag{}, "output-dir", true},
+		{&api.Flag{Shorthand: "o"}, "output-dir", true},
+		{&api.Flag{Name: "out"}, "out", true},
+	} {
+		e := api.ConfigEntry{
+			KeyValue: api.KeyValue{Key: "output-dir", Type: api.StringValue},
+			Flag:     test.flag,
+		}
+
+		got, ok := e.FlagName()
+		if got != test.want || ok != test.wantOK {
+			t.Errorf("%#v: got (%q, %t), want (%q, %t)", test.flag, got, ok, test.want, test.wantOK)
+		}
+	}
+}
+
+func globalManifest(command ...api.ConfigEntry) *api.Manifest {
+	return &api.Manifest{
+		Name:   "Example",
+		Domain: "example",
+		GlobalConfig: []api.ConfigEntry{
+			{
+				KeyValue: api.KeyValue{Key: "verbose", Type: api.BoolValue, Value: false},
+				Flag:     &api.Flag{Shorthand: "v"},
+			},
+		},
+		Commands: []api.Command{{Name: "build", Aliases: []string{"b"}, Config: command}},
+	}
+}
+
+func TestManifestCommandConfig(t *testing.T) {
+	t.Parallel()
+
+	m := globalManifest(api.ConfigEntry{
+		KeyValue: api.KeyValue{Key: "target", Type: api.StringValue, Value: "all"},
+		Flag:     &api.Flag{Shorthand: "t"},
+	})
+
+	for _, name := range []string{"build", "b"} {
+		got, err := m.CommandConfig(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		keys := make([]string, 0, len(got))
+		for _, e := range got {
+			keys = append(keys, e.Key)
+		}
+
+		if want := []string{"verbose", "target"}; !reflect.DeepEqual(keys, want) {
+			t.Errorf("%s: got %v, want %v", name, keys, want)
+		}
+	}
+
+	_, err := m.CommandConfig("missing")
+	if err == nil || !strings.Contains(err.Error(), "not found") {
+		t.Errorf("got %v, want command not found error", err)
+	}
+
+	if err := m.Validate(); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestManifestValidateGlobalFlagCollision(t *testing.T) {
+	t.Parallel()
+
+	for _, test := range []struct {
+		entry api.ConfigEntry
+		want  string // error string should contain this
+	}{
+		{
+			api.ConfigEntry{
+				KeyValue: api.KeyValue{Key: "verbose", Type: api.BoolValue},
+				Flag:     &api.Flag{},
+			},
+			"--verbose",
+		},
+		{
+			api.ConfigEntry{
+				KeyValue: api.KeyValue{Key: "loud", Type: api.BoolValue},
+				Flag:     &api.Flag{Name: "verbose"},
+			},
+			"--verbose",
+		},
+		{
+			api.ConfigEntry{
+				KeyValue: api.KeyValue{Key: "version", Type: api.BoolValue},
+				Flag:     &api.Flag{Shorthand: "v"},
+			},
+			"-v is used",
+		},
+	} {
+		err := globalManifest(test.entry).Validate()
+		if err == nil || !strings.Contains(err.Error(), test.want) {
+			t.Errorf("%#v: got %v, want string containing %q", test.entry, err, test.want)
+		}
+	}
+}
+
+func TestConfigEntryValidateValueConstraints(t *testing.T) {
+	t.Parallel()
+
+	one, ten := 1.0, 10.0
+	port := api.ConfigEntry{
+		KeyValue: api.KeyValue{Key: "port", Type: api.UintValue},
+		Min:      &one,
+		Max:      &ten,
+	}
+	name := api.ConfigEntry{
+		KeyValue: api.KeyValue{Key: "name", Type: api.StringValue},
+		Pattern:  `^[a-z]+$`,
+	}
+
+	for _, test := range []struct {
+		entry api.ConfigEntry
+		in    any
+		want  string // error string should contain this, empty for no error
+	}{
+		{port, 1.0, ""},
+		{port, 10.0, ""},
+		{port, 0.0, "less than"},
+		{port, 11.0, "greater than"},
+		{name, "abc", ""},
+		{name, "abc1", "does not match pattern"},
+		{name, nil, ""},
+	} {
+		kv := api.KeyValue{Key: test.entry.Key, Type: test.entry.Type, Value: test.in}
+
+		err := test.entry.ValidateValue(&kv)
+		if test.want == "" {
+			if err != nil {
+				t.Errorf("%s, %v: unexpected error: %v", test.entry.Key, test.in, err)
+			}
+
+			continue
+		}
+
+		if err == nil || !strings.Contains(err.Error(), test.want) {
+			t.Errorf(
+				"%s, %v: got %v, want string containing %q",
+				test.entry.Key,
+				test.in,
+				err,
+				test.want,
+			)
+		}
+	}
+}
+
+func TestConfigEntryMissingError(t *testing.T) {
+	t.Parallel()
+
+	const tokenMessage = "set your API token via REGINALD_MYPLUGIN_TOKEN or the config file"
+
+	for _, test := range []struct {
+		entry api.ConfigEntry
+		want  string
+	}{
+		{
+			api.ConfigEntry{
+				KeyValue:        api.KeyValue{Key: "token", Type: api.StringValue},
+				Required:        true,
+				RequiredMessage: tokenMessage,
+			},
+			tokenMessage,
+		},
+		{
+			api.ConfigEntry{
+				KeyValue: api.KeyValue{Key: "token", Type: api.StringValue},
+				Required: true,
+			},
+			`missing required value: key "token"`,
+		},
+		{
+			api.ConfigEntry{
+				KeyValue:    api.KeyValue{Key: "token", Type: api.StringValue},
+				Flag:        &api.Flag{},
+				EnvOverride: "TOKEN",
+				Required:    true,
+			},
+			`missing required value: key "token", set it with --token or REGINALD_TOKEN`,
+		},
+		{
+			api.ConfigEntry{
+				KeyValue: api.KeyValue{Key: "token", Type: api.StringValue},
+				Flag:     &api.Flag{Name: "api-token"},
+				FlagOnly: true,
+				Required: true,
+			},
+			`missing required value: key "token", set it with --api-token`,
+		},
+	} {
+		if got := test.entry.MissingError().Error(); got != test.want {
+			t.Errorf("got %q, want %q", got, test.want)
+		}
+	}
+}
+
+func TestManifestMissingError(t *testing.T) {
+	t.Parallel()
+
+	token := api.ConfigEntry{
+		KeyValue: api.KeyValue{Key: "token", Type: api.StringValue},
+		Flag:     &api.Flag{},
+		Required: true,
+	}
+	m := inheritManifest(token)
+	m.Config = append(m.Config, token)
+
+	for _, test := range []struct {
+		command string
+		entry   api.ConfigEntry
+		want    string
+	}{
+		{
+			"",
+			token,
+			`missing required value: key "token", set it with --token or REGINALD_EXAMPLE_TOKEN`,
+		},
+		{
+			"build",
+			token,
+			`missing required value: key "token", ` +
+				`set it with --token or REGINALD_EXAMPLE_BUILD_TOKEN`,
+		},
+		{
+			"build",
+			api.ConfigEntry{Inherit: "verbose"},
+			`missing required value: key "verbose", set it with --verbose or REGINALD_VERBOSE`,
+		},
+		{
+			"",
+			api.ConfigEntry{
+				KeyValue: api.KeyValue{Key: "token"},
+				Flag:     &api.Flag{},
+				FlagOnly: true,
+			},
+			`missing required value: key "token", set it with --token`,
+		},
+	} {
+		if got := m.MissingError(test.command, test.entry).Error(); got != test.want {
+			t.Errorf("got %q, want %q", got, test.want)
+		}
+	}
+}
+
+func TestConfigEntryResolveFromFile(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+
+	write := func(name, content string) string {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+
+		return path
+	}
+
+	entry := api.ConfigEntry{
+		KeyValue: api.KeyValue{Key: "token", Type: api.StringValue},
+		FileRef:  true,
+		Pattern:  `^[a-z0-9]+$`,
+	}
+
+	for _, test := range []struct {
+		content string
+		want    string
+	}{
+		{"abc123\n", "abc123"},
+		{"abc123\r\n", "abc123"},
+		{"abc123", "abc123"},
+	} {
+		kv, err := entry.ResolveFromFile(write("token", test.content))
+		if err != nil {
+			t.Fatalf("%q: %v", test.content, err)
+		}
+
+		if kv.Key != "token" || kv.Type != api.StringValue || kv.Value != test.want {
+			t.Errorf("%q: got %+v, want value %q", test.content, kv, test.want)
+		}
+	}
+
+	for _, test := range []struct {
+		entry api.ConfigEntry
+		path  string
+		want  string // error string should contain this
+	}{
+		{entry, write("bad", "abc\n\n"), "does not match pattern"},
+		{entry, filepath.Join(dir, "missing"), "failed to read"},
+		{
+			api.ConfigEntry{KeyValue: entry.KeyValue},
+			write("plain", "abc"),
+			"cannot be read from a file",
+		},
+	} {
+		_, err := test.entry.ResolveFromFile(test.path)
+		if err == nil || !strings.Contains(err.Error(), test.want) {
+			t.Errorf("%s: got %v, want string containing %q", test.path, err, test.want)
+		}
+	}
+
+	m := &api.Manifest{
+		Name:   "Example",
+		Domain: "example",
+		Config: []api.ConfigEntry{
+			{KeyValue: api.KeyValue{Key: "n", Type: api.IntValue}, FileRef: true},
+		},
+	}
+	want := "file reference requires a string value"
+	if err := m.Validate(); err == nil || !strings.Contains(err.Error(), want) {
+		t.Errorf("got %v, want file reference type error", err)
+	}
+}
+
+func TestConfigEntryDefault(t *testing.T) {
+	t.Parallel()
+
+	//nolint:govet // don't care about this in tests
+	for _, test := range []struct {
+		entry api.ConfigEntry
+		want  any
+		ok    bool
+	}{
+		{api.ConfigEntry{KeyValue: api.KeyValue{Key: "a", Type: api.BoolValue}}, nil, false},
+		{
+			api.ConfigEntry{KeyValue: api.KeyValue{Key: "a", Type: api.BoolValue, Value: false}},
+			false,
+			true,
+		},
+		{
+			api.ConfigEntry{KeyValue: api.KeyValue{Key: "a", Type: api.IntValue, Value: 0.0}},
+			0,
+			true,
+		},
+		{
+			api.ConfigEntry{KeyValue: api.KeyValue{Key: "a", Type: api.UintValue, Value: 3.0}},
+			uint64(3),
+			true,
+		},
+		{
+			api.ConfigEntry{KeyValue: api.KeyValue{Key: "a", Type: api.StringValue, Value: ""}},
+			"",
+			true,
+		},
+	} {
+		kv, ok := test.entry.Default()
+		if ok != test.ok || !reflect.DeepEqual(kv.Value, test.want) {
+			t.Errorf("%#v: got %#v, %t, want %#v, %t", test.entry, kv.Value, ok, test.want, test.ok)
+		}
+
+		if kv.Key != test.entry.Key || kv.Type != test.entry.Type {
+			t.Errorf("%#v: got %+v, want the key and type of the entry", test.entry, kv)
+		}
+	}
+
+	var e api.ConfigEntry
+
+	if err := json.Unmarshal([]byte(`{"key": "a", "type": "int", "value": null}`), &e); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, ok := e.Default(); ok {
+		t.Error("null value: got a default, want none")
+	}
+}
+
+func TestManifestRequiredConfig(t *testing.T) {
+	t.Parallel()
+
+	m := &api.Manifest{
+		Name:   "Example",
+		Domain: "example",
+		Config: []api.ConfigEntry{
+			api.NewConfigEntry("token", api.StringValue, api.Required("")),
+			api.NewConfigEntry("region", api.StringValue, api.Required(""), api.WithDefault("eu")),
+			api.NewConfigEntry("jobs", api.IntValue),
+			api.NewConfigEntry("verbose", api.BoolValue, api.Required(""), api.WithDefault(false)),
+		},
+		GlobalConfig: []api.ConfigEntry{
+			api.NewConfigEntry("user", api.StringValue, api.Required("")),
+		},
+		Commands: []api.Command{
+			{
+				Name: "upload",
+				Config: []api.ConfigEntry{
+					api.NewConfigEntry("target", api.PathValue, api.Required("")),
+					{KeyValue: api.KeyValue{Key: "upload-token"}, Inherit: "token"},
+				},
+			},
+		},
+		Tasks: []api.Task{
+			{
+				Type: "link",
+				Config: []api.ConfigEntry{
+					api.NewConfigEntry("src", api.PathValue, api.Required("")),
+				},
+			},
+		},
+	}
+
+	var got []string
+
+	for _, e := range m.RequiredConfig() {
+		got = append(got, e.Key)
+	}
+
+	if want := []string{"token", "user", "target", "upload-token"}; !slices.Equal(got, want) {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,268 @@
+// Copyright 2025 Antti Kivi
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"math"
+	"slices"
+	"strconv"
+	"unicode/utf8"
+)
+
+// hexDigits are the digits used in the \u escapes of the JSON strings.
+const hexDigits = "0123456789abcdef"
+
+// Errors returned by the JSON encoding.
+var errUnsupportedFloat = errors.New("unsupported float value")
+
+// AppendJSON appends the JSON encoding of the KeyValue to b and returns
+// the extended buffer. The output is the same as the output of
+// [encoding/json.Marshal] byte for byte, but the keys, the types, and
+// the values of the types that a KeyValue holds are written directly without
+// reflection, so it is meant for encoding many KeyValues, for example, in
+// bulk. The values are encoded by their dynamic Go types: nil, bool, string,
+// the integer types, float64, map[string]any, and []any are handled directly
+// and any other value is encoded with encoding/json. Like in
+// [KeyValue.MarshalJSON], a nil Value is encoded as the zero value of Type.
+// It returns an error if the value cannot be encoded.
+func (kv KeyValue) AppendJSON(b []byte) ([]byte, error) {
+	b = append(b, `{"key":`...)
+	b = appendJSONString(b, kv.Key)
+	b = append(b, `,"value":`...)
+
+	v := kv.Value
+	if v == nil {
+		v = kv.Type.ZeroValue()
+	}
+
+	b, err := appendJSONValue(b, v)
+	if err != nil {
+		return nil, fmt.Errorf("key %q: %w", kv.Key, err)
+	}
+
+	b = append(b, `,"type":`...)
+	b = appendJSONString(b, string(kv.Type))
+
+	if len(kv.Fields) > 0 {
+		b = append(b, `,"fields":[`...)
+
+		for i, f := range kv.Fields {
+			if i > 0 {
+				b = append(b, ',')
+			}
+
+			b, err = f.AppendJSON(b)
+			if err != nil {
+				return nil, fmt.Errorf("key %q: %w", kv.Key, err)
+			}
+		}
+
+		b = append(b, ']')
+	}
+
+	return append(b, '}'), nil
+}
+
+// AppendJSON appends the JSON encoding of the ConfigEntry to b and returns
+// the extended buffer. It is needed as the embedded [KeyValue] has its own
+// AppendJSON that would otherwise be promoted and encode only the KeyValue.
+// The ConfigEntries are not encoded on the hot paths, so all of the fields
+// are encoded with [encoding/json] and the output is the same as the output
+// of [encoding/json.Marshal]. It returns an error if the entry cannot be
+// encoded.
+func (e ConfigEntry) AppendJSON(b []byte) ([]byte, error) {
+	data, err := json.Marshal(e)
+	if err != nil {
+		return nil, fmt.Errorf("key %q: %w", e.Key, err)
+	}
+
+	return append(b, data...), nil
+}
+
+// appendJSONValue appends the JSON encoding of v to b.
+func appendJSONValue(b []byte, v any) ([]byte, error) {
+	switch x := v.(type) {
+	case nil:
+		return append(b, "null"...), nil
+	case bool:
+		return strconv.AppendBool(b, x), nil
+	case string:
+		return appendJSONString(b, x), nil
+	case int:
+		return strconv.AppendInt(b, int64(x), 10), nil
+	case int8:
+		return strconv.AppendInt(b, int64(x), 10), nil
+	case int16:
+		return strconv.AppendInt(b, int64(x), 10), nil
+	case int32:
+		return strconv.AppendInt(b, int64(x), 10), nil
+	case int64:
+		return strconv.AppendInt(b, x, 10), nil
+	case uint:
+		return strconv.AppendUint(b, uint64(x), 10), nil
+	case uint8:
+		return strconv.AppendUint(b, uint64(x), 10), nil
+	case uint16:
+		return strconv.AppendUint(b, uint64(x), 10), nil
+	case uint32:
+		return strconv.AppendUint(b, uint64(x), 10), nil
+	case uint64:
+		return strconv.AppendUint(b, x, 10), nil
+	case float64:
+		return appendJSONFloat(b, x)
+	case map[string]any:
+		if x == nil {
+			return append(b, "null"...), nil
+		}
+
+		b = append(b, '{')
+
+		for i, k := range slices.Sorted(maps.Keys(x)) {
+			if i > 0 {
+				b = append(b, ',')
+			}
+
+			b = appendJSONString(b, k)
+			b = append(b, ':')
+
+			var err error
+
+			b, err = appendJSONValue(b, x[k])
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		return append(b, '}'), nil
+	case []any:
+		if x == nil {
+			return append(b, "null"...), nil
+		}
+
+		b = append(b, '[')
+
+		for i, e := range x {
+			if i > 0 {
+				b = append(b, ',')
+			}
+
+			var err error
+
+			b, err = appendJSONValue(b, e)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		return append(b, ']'), nil
+	default:
+		data, err := json.Marshal(v)
+		if err != nil {
+			return nil, fmt.Errorf("%w", err)
+		}
+
+		return append(b, data...), nil
+	}
+}
+
+// appendJSONFloat appends f to b in the format of encoding/json.
+func appendJSONFloat(b []byte, f float64) ([]byte, error) {
+	if math.IsInf(f, 0) || math.IsNaN(f) {
+		return nil, fmt.Errorf("%w: %s", errUnsupportedFloat, strconv.FormatFloat(f, 'g', -1, 64))
+	}
+
+	format := byte('f')
+	if abs := math.Abs(f); abs != 0 && (abs < 1e-6 || abs >= 1e21) {
+		format = 'e'
+	}
+
+	b = strconv.AppendFloat(b, f, format, -1, 64)
+
+	// Like encoding/json, clean up e-09 to e-9.
+	if n := len(b); format == 'e' && n >= 4 && b[n-4] == 'e' && b[n-3] == '-' && b[n-2] == '0' {
+		b[n-2] = b[n-1]
+		b = b[:n-1]
+	}
+
+	return b, nil
+}
+
+// appendJSONString appends s to b as a JSON string escaped in the same way as
+// encoding/json escapes it by default, including the characters special to
+// HTML and the invalid UTF-8.
+func appendJSONString(b []byte, s string) []byte {
+	b = append(b, '"')
+	start := 0
+
+	for i := 0; i < len(s); {
+		if c := s[i]; c < utf8.RuneSelf {
+			if c >= ' ' && c != '"' && c != '\\' && c != '<' && c != '>' && c != '&' {
+				i++
+
+				continue
+			}
+
+			b = append(b, s[start:i]...)
+
+			switch c {
+			case '"', '\\':
+				b = append(b, '\\', c)
+			case '\b':
+				b = append(b, '\\', 'b')
+			case '\f':
+				b = append(b, '\\', 'f')
+			case '\n':
+				b = append(b, '\\', 'n')
+			case '\r':
+				b = append(b, '\\', 'r')
+			case '\t':
+				b = append(b, '\\', 't')
+			default:
+				b = append(b, '\\', 'u', '0', '0', hexDigits[c>>4], hexDigits[c&0xF])
+			}
+
+			i++
+			start = i
+
+			continue
+		}
+
+		r, size := utf8.DecodeRuneInString(s[i:])
+
+		switch {
+		case r == utf8.RuneError && size == 1:
+			b = append(b, s[start:i]...)
+			b = append(b, "\ufffd"...)
+		case r == '\u2028' || r == '\u2029':
+			b = append(b, s[start:i]...)
+			b = append(b, '\\', 'u', '2', '0', '2', hexDigits[r&0xF])
+		default:
+			i += size
+
+			continue
+		}
+
+		i += size
+		start = i
+	}
+
+	b = append(b, s[start:]...)
+
+	return append(b, '"')
+}
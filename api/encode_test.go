@@ -0,0 +1,189 @@
+// Copyright 2025 Antti Kivi
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api_test
+
+import (
+	"encoding/json"
+	"math"
+	"testing"
+
+	"github.com/reginald-project/reginald-sdk-go/api"
+)
+
+func TestKeyValueAppendJSON(t *testing.T) {
+	t.Parallel()
+
+	for _, kv := range []api.KeyValue{
+		{Key: "unset", Value: nil, Type: api.StringValue},
+		{Key: "verbose", Value: true, Type: api.BoolValue},
+		{Key: "quiet", Value: false, Type: api.BoolValue},
+		{Key: "jobs", Value: 4, Type: api.IntValue},
+		{Key: "offset", Value: int64(math.MinInt64), Type: api.IntValue},
+		{Key: "small", Value: int8(-8), Type: api.IntValue},
+		{Key: "size", Value: uint64(math.MaxUint64), Type: api.UintValue},
+		{Key: "mode", Value: uint16(420), Type: api.UintValue},
+		{Key: "decoded", Value: 5.0, Type: api.IntValue},
+		{Key: "ratio", Value: 0.25, Type: api.IntValue},
+		{Key: "negative-zero", Value: math.Copysign(0, -1), Type: api.IntValue},
+		{Key: "large", Value: 1e21, Type: api.IntValue},
+		{Key: "tiny", Value: 1e-7, Type: api.IntValue},
+		{Key: "precise", Value: 123456789.125, Type: api.IntValue},
+		{Key: "float32", Value: float32(1.5), Type: api.IntValue},
+		{Key: "number", Value: json.Number("12"), Type: api.IntValue},
+		{Key: "empty", Value: "", Type: api.StringValue},
+		{Key: "path", Value: "~/.config/reginald", Type: api.PathValue},
+		{Key: "quotes", Value: `say "hi" \ bye`, Type: api.StringValue},
+		{Key: "control", Value: "a\tb\nc\rd\be\ff\x00g\x1f", Type: api.StringValue},
+		{Key: "html", Value: "<a href='x'>&amp;</a>", Type: api.StringValue},
+		{Key: "unicode", Value: "päivää 🌞 \u2028\u2029", Type: api.StringValue},
+		{Key: "invalid\xff", Value: "bad \xff\xfe utf-8", Type: api.StringValue},
+		{
+			Key: "owner",
+			Value: map[string]any{
+				"name":   "Antti",
+				"uid":    1000.0,
+				"groups": []any{"wheel", 10.0, nil},
+				"sub":    nil,
+			},
+			Type: api.ObjectValue,
+			Fields: []api.KeyValue{
+				{Key: "name", Value: "", Type: api.StringValue},
+				{Key: "uid", Value: nil, Type: api.IntValue},
+			},
+		},
+		{Key: "nil-map", Value: map[string]any(nil), Type: api.ObjectValue},
+	} {
+		want, err := json.Marshal(kv)
+		if err != nil {
+			t.Fatalf("%s: json.Marshal: %v", kv.Key, err)
+		}
+
+		got, err := kv.AppendJSON([]byte("prefix"))
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", kv.Key, err)
+
+			continue
+		}
+
+		if string(got) != "prefix"+string(want) {
+			t.Errorf("%s: got %s, want prefix%s", kv.Key, got, want)
+		}
+	}
+}
+
+func TestKeyValueAppendJSONError(t *testing.T) {
+	t.Parallel()
+
+	for _, kv := range []api.KeyValue{
+		{Key: "nan", Value: math.NaN(), Type: api.IntValue},
+		{Key: "inf", Value: math.Inf(1), Type: api.IntValue},
+		{Key: "chan", Value: make(chan int), Type: api.IntValue},
+		{Key: "nested", Value: map[string]any{"a": []any{math.Inf(-1)}}, Type: api.ObjectValue},
+	} {
+		if _, err := json.Marshal(kv); err == nil {
+			t.Fatalf("%s: json.Marshal: got nil error, want error", kv.Key)
+		}
+
+		if _, err := kv.AppendJSON(nil); err == nil {
+			t.Errorf("%s: got nil error, want error", kv.Key)
+		}
+	}
+}
+
+func TestConfigEntryAppendJSON(t *testing.T) {
+	t.Parallel()
+
+	limit := 8.0
+	e := api.ConfigEntry{
+		KeyValue:      api.KeyValue{Key: "jobs", Value: 4, Type: api.IntValue},
+		Description:   "Number of jobs.",
+		Flag:          &api.Flag{Name: "jobs", Shorthand: "j"},
+		AllowedValues: []any{2, 4, 8},
+		Max:           &limit,
+		Required:      true,
+	}
+
+	want, err := json.Marshal(e)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := e.AppendJSON([]byte("prefix"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if string(got) != "prefix"+string(want) {
+		t.Errorf("got %s, want prefix%s", got, want)
+	}
+
+	e.Value = math.NaN()
+
+	if _, err := e.AppendJSON(nil); err == nil {
+		t.Error("got nil error, want error")
+	}
+}
+
+func BenchmarkKeyValueAppendJSON(b *testing.B) {
+	kvs := []api.KeyValue{
+		{Key: "verbose", Value: true, Type: api.BoolValue},
+		{Key: "jobs", Value: 4, Type: api.IntValue},
+		{Key: "size", Value: uint64(1 << 40), Type: api.UintValue},
+		{Key: "path", Value: "~/.config/reginald", Type: api.PathValue},
+		{
+			Key:   "owner",
+			Value: map[string]any{"name": "Antti", "uid": 1000.0},
+			Type:  api.ObjectValue,
+		},
+	}
+
+	b.Run("AppendJSON", func(b *testing.B) {
+		b.ReportAllocs()
+
+		var buf []byte
+
+		for b.Loop() {
+			buf = buf[:0]
+
+			for _, kv := range kvs {
+				var err error
+
+				buf, err = kv.AppendJSON(buf)
+				if err != nil {
+					b.Fatal(err)
+				}
+			}
+		}
+	})
+
+	b.Run("json.Marshal", func(b *testing.B) {
+		b.ReportAllocs()
+
+		var buf []byte
+
+		for b.Loop() {
+			buf = buf[:0]
+
+			for _, kv := range kvs {
+				data, err := json.Marshal(kv)
+				if err != nil {
+					b.Fatal(err)
+				}
+
+				buf = append(buf, data...)
+			}
+		}
+	})
+}
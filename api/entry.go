@@ -0,0 +1,193 @@
+// Copyright 2025 Antti Kivi
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+// EntryOption is an option for [NewConfigEntry] that sets a field of
+// the ConfigEntry. The options do not check the fields that they set; invalid
+// combinations, like a minimum value for a string entry, are reported by
+// [Manifest.Validate].
+type EntryOption func(e *ConfigEntry)
+
+// NewConfigEntry returns a ConfigEntry with the given key and type and with
+// the given options applied in order.
+func NewConfigEntry(key string, t ValueType, opts ...EntryOption) ConfigEntry {
+	//nolint:exhaustruct // the rest are set by the options
+	e := ConfigEntry{KeyValue: KeyValue{Key: key, Value: nil, Type: t, Fields: nil}}
+
+	for _, opt := range opts {
+		opt(&e)
+	}
+
+	return e
+}
+
+// NewBoolEntry returns a [BoolValue] ConfigEntry with the given key, default
+// value, and description.
+func NewBoolEntry(key string, def bool, description string) ConfigEntry {
+	return newTypedEntry(NewBoolKeyValue(key, def), description)
+}
+
+// NewIntEntry returns an [IntValue] ConfigEntry with the given key and default
+// value. The description is set as in [NewBoolEntry].
+func NewIntEntry(key string, def int, description string) ConfigEntry {
+	return newTypedEntry(NewIntKeyValue(key, def), description)
+}
+
+// NewStringEntry returns a [StringValue] ConfigEntry with the given key and
+// default value. The description is set as in [NewBoolEntry].
+func NewStringEntry(key, def, description string) ConfigEntry {
+	return newTypedEntry(NewStringKeyValue(key, def), description)
+}
+
+// NewBoolKeyValue returns a [BoolValue] KeyValue with the given key and value,
+// for example, for the config of a task.
+func NewBoolKeyValue(key string, v bool) KeyValue {
+	return KeyValue{Key: key, Value: v, Type: BoolValue, Fields: nil}
+}
+
+// NewIntKeyValue returns an [IntValue] KeyValue with the given key and value.
+func NewIntKeyValue(key string, v int) KeyValue {
+	return KeyValue{Key: key, Value: v, Type: IntValue, Fields: nil}
+}
+
+// NewStringKeyValue returns a [StringValue] KeyValue with the given key and
+// value.
+func NewStringKeyValue(key, v string) KeyValue {
+	return KeyValue{Key: key, Value: v, Type: StringValue, Fields: nil}
+}
+
+// WithDescription sets the Description of the ConfigEntry.
+func WithDescription(description string) EntryOption {
+	return func(e *ConfigEntry) {
+		e.Description = description
+	}
+}
+
+// WithDefault sets the default value of the ConfigEntry.
+func WithDefault(v any) EntryOption {
+	return func(e *ConfigEntry) {
+		e.Value = v
+	}
+}
+
+// WithFields sets the Fields of an [ObjectValue] ConfigEntry.
+func WithFields(fields ...KeyValue) EntryOption {
+	return func(e *ConfigEntry) {
+		e.Fields = fields
+	}
+}
+
+// WithFlag gives the ConfigEntry a flag with the given long name and
+// description. If name is empty, the key of the ConfigEntry is used as
+// the name of the flag.
+func WithFlag(name, description string) EntryOption {
+	return func(e *ConfigEntry) {
+		f := ensureFlag(e)
+		f.Name = name
+		f.Description = description
+	}
+}
+
+// WithShorthand sets the shorthand of the flag of the ConfigEntry. If
+// the ConfigEntry has no flag, a flag named after the key is added.
+func WithShorthand(shorthand string) EntryOption {
+	return func(e *ConfigEntry) {
+		ensureFlag(e).Shorthand = shorthand
+	}
+}
+
+// WithInverse sets the inverse name of the flag of a boolean ConfigEntry. If
+// the ConfigEntry has no flag, a flag named after the key is added.
+func WithInverse(inverse string) EntryOption {
+	return func(e *ConfigEntry) {
+		ensureFlag(e).Inverse = inverse
+	}
+}
+
+// WithEnvOverride sets the EnvOverride of the ConfigEntry.
+func WithEnvOverride(name string) EntryOption {
+	return func(e *ConfigEntry) {
+		e.EnvOverride = name
+	}
+}
+
+// WithAllowed sets the AllowedValues of the ConfigEntry.
+func WithAllowed(values ...any) EntryOption {
+	return func(e *ConfigEntry) {
+		e.AllowedValues = values
+	}
+}
+
+// WithMin sets the minimum value of a numeric ConfigEntry.
+func WithMin(min float64) EntryOption { //nolint:predeclared // min is the clearest name
+	return func(e *ConfigEntry) {
+		e.Min = &min
+	}
+}
+
+// WithMax sets the maximum value of a numeric ConfigEntry.
+func WithMax(max float64) EntryOption { //nolint:predeclared // max is the clearest name
+	return func(e *ConfigEntry) {
+		e.Max = &max
+	}
+}
+
+// WithPattern sets the Pattern of a string ConfigEntry.
+func WithPattern(pattern string) EntryOption {
+	return func(e *ConfigEntry) {
+		e.Pattern = pattern
+	}
+}
+
+// Required makes the ConfigEntry required. If message is not empty, it is set
+// as the RequiredMessage of the ConfigEntry.
+func Required(message string) EntryOption {
+	return func(e *ConfigEntry) {
+		e.Required = true
+		e.RequiredMessage = message
+	}
+}
+
+// Sensitive marks the value of the ConfigEntry as a secret.
+func Sensitive() EntryOption {
+	return func(e *ConfigEntry) {
+		e.Sensitive = true
+	}
+}
+
+// FlagOnly makes the ConfigEntry settable only with its flag. If
+// the ConfigEntry has no flag, a flag named after the key is added.
+func FlagOnly() EntryOption {
+	return func(e *ConfigEntry) {
+		ensureFlag(e)
+		e.FlagOnly = true
+	}
+}
+
+// newTypedEntry returns a ConfigEntry with the given default value and
+// description.
+func newTypedEntry(kv KeyValue, description string) ConfigEntry {
+	//nolint:exhaustruct // only the default value and the description are set
+	return ConfigEntry{KeyValue: kv, Description: description}
+}
+
+// ensureFlag returns the Flag of e, adding an empty Flag first if e has none.
+func ensureFlag(e *ConfigEntry) *Flag {
+	if e.Flag == nil {
+		e.Flag = &Flag{Name: "", Shorthand: "", Description: "", Inverse: ""}
+	}
+
+	return e.Flag
+}
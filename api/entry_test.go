@@ -0,0 +1,208 @@
+// Copyright 2025 Antti Kivi
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api_test
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/reginald-project/reginald-sdk-go/api"
+)
+
+func TestNewConfigEntry(t *testing.T) {
+	t.Parallel()
+
+	minJobs, maxJobs := 1.0, 8.0
+
+	for _, test := range []struct {
+		got  api.ConfigEntry
+		want api.ConfigEntry
+	}{
+		{
+			api.NewConfigEntry(
+				"jobs",
+				api.IntValue,
+				api.WithDefault(4),
+				api.WithMin(1),
+				api.WithMax(8),
+			),
+			api.ConfigEntry{
+				KeyValue: api.KeyValue{Key: "jobs", Value: 4, Type: api.IntValue},
+				Min:      &minJobs,
+				Max:      &maxJobs,
+			},
+		},
+		{
+			api.NewConfigEntry(
+				"format",
+				api.StringValue,
+				api.WithShorthand("f"),
+				api.WithFlag("output-format", "Output format."),
+				api.WithAllowed("text", "json"),
+			),
+			api.ConfigEntry{
+				KeyValue: api.KeyValue{Key: "format", Type: api.StringValue},
+				Flag: &api.Flag{
+					Name:        "output-format",
+					Shorthand:   "f",
+					Description: "Output format.",
+				},
+				AllowedValues: []any{"text", "json"},
+			},
+		},
+		{
+			api.NewConfigEntry(
+				"token",
+				api.StringValue,
+				api.Required("Set the token."),
+				api.Sensitive(),
+				api.WithEnvOverride("TOKEN"),
+			),
+			api.ConfigEntry{
+				KeyValue:        api.KeyValue{Key: "token", Type: api.StringValue},
+				EnvOverride:     "TOKEN",
+				Required:        true,
+				RequiredMessage: "Set the token.",
+				Sensitive:       true,
+			},
+		},
+		{
+			api.NewConfigEntry("color", api.BoolValue, api.WithInverse("no-color"), api.FlagOnly()),
+			api.ConfigEntry{
+				KeyValue: api.KeyValue{Key: "color", Type: api.BoolValue},
+				Flag:     &api.Flag{Inverse: "no-color"},
+				FlagOnly: true,
+			},
+		},
+	} {
+		if !reflect.DeepEqual(test.got, test.want) {
+			t.Errorf("got %+v, want %+v", test.got, test.want)
+		}
+
+		m := &api.Manifest{Name: "Example", Domain: "example", Config: []api.ConfigEntry{test.got}}
+		if err := m.Validate(); err != nil {
+			t.Errorf("%s: unexpected error: %v", test.got.Key, err)
+		}
+	}
+}
+
+func TestNewTypedEntry(t *testing.T) {
+	t.Parallel()
+
+	for _, test := range []struct {
+		got         api.ConfigEntry
+		typ         api.ValueType
+		value       any
+		description string
+	}{
+		{api.NewBoolEntry("dry-run", true, "Dry run."), api.BoolValue, true, "Dry run."},
+		{api.NewBoolEntry("color", false, ""), api.BoolValue, false, ""},
+		{api.NewIntEntry("jobs", 4, "Number of jobs."), api.IntValue, 4, "Number of jobs."},
+		{api.NewIntEntry("retries", 0, ""), api.IntValue, 0, ""},
+		{api.NewStringEntry("format", "text", "Format."), api.StringValue, "text", "Format."},
+		{api.NewStringEntry("prefix", "", ""), api.StringValue, "", ""},
+	} {
+		if test.got.Type != test.typ {
+			t.Errorf("%s: got type %q, want %q", test.got.Key, test.got.Type, test.typ)
+		}
+
+		kv, ok := test.got.Default()
+		if !ok || kv.Value != test.value {
+			t.Errorf("%s: got default %v (%t), want %v", test.got.Key, kv.Value, ok, test.value)
+		}
+
+		if test.got.Description != test.description || test.got.Flag != nil {
+			t.Errorf(
+				"%s: got description %q and flag %+v, want %q and no flag",
+				test.got.Key,
+				test.got.Description,
+				test.got.Flag,
+				test.description,
+			)
+		}
+
+		m := &api.Manifest{Name: "Example", Domain: "example", Config: []api.ConfigEntry{test.got}}
+		if err := m.Validate(); err != nil {
+			t.Errorf("%s: unexpected error: %v", test.got.Key, err)
+		}
+	}
+}
+
+func TestNewTypedKeyValue(t *testing.T) {
+	t.Parallel()
+
+	for _, test := range []struct {
+		got  api.KeyValue
+		want api.KeyValue
+	}{
+		{
+			api.NewBoolKeyValue("force", true),
+			api.KeyValue{Key: "force", Value: true, Type: api.BoolValue},
+		},
+		{
+			api.NewIntKeyValue("mode", 420),
+			api.KeyValue{Key: "mode", Value: 420, Type: api.IntValue},
+		},
+		{
+			api.NewStringKeyValue("path", "~/.config"),
+			api.KeyValue{Key: "path", Value: "~/.config", Type: api.StringValue},
+		},
+	} {
+		if !reflect.DeepEqual(test.got, test.want) {
+			t.Errorf("got %+v, want %+v", test.got, test.want)
+		}
+
+		m := &api.Manifest{
+			Name:   "Example",
+			Domain: "example",
+			Tasks:  []api.Task{{Type: "link", Config: []api.ConfigEntry{{KeyValue: test.got}}}},
+		}
+		if err := m.Validate(); err != nil {
+			t.Errorf("%s: unexpected error: %v", test.got.Key, err)
+		}
+	}
+}
+
+func TestNewConfigEntryInvalid(t *testing.T) {
+	t.Parallel()
+
+	for _, test := range []struct {
+		entry api.ConfigEntry
+		want  string // error string should contain this
+	}{
+		{
+			api.NewConfigEntry("name", api.StringValue, api.WithMin(1)),
+			"constraint does not match the value type",
+		},
+		{
+			api.NewConfigEntry("jobs", api.IntValue, api.WithPattern("^[0-9]+$")),
+			"constraint does not match the value type",
+		},
+		{api.NewConfigEntry("jobs", api.IntValue, api.WithDefault("four")), "does not match"},
+		{api.NewConfigEntry("jobs", api.IntValue, api.WithInverse("no-jobs")), "config[0]"},
+	} {
+		m := &api.Manifest{
+			Name:   "Example",
+			Domain: "example",
+			Config: []api.ConfigEntry{test.entry},
+		}
+
+		err := m.Validate()
+		if err == nil || !strings.Contains(err.Error(), test.want) {
+			t.Errorf("%+v: got %v, want string containing %q", test.entry, err, test.want)
+		}
+	}
+}
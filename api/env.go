@@ -0,0 +1,261 @@
+// Copyright 2025 Antti Kivi
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"strings"
+)
+
+// EnvNamePrefix is the prefix of all of the environment variables that Reginald
+// reads the config values from.
+const EnvNamePrefix = "REGINALD_"
+
+// Errors returned by the environment variable utilities.
+var (
+	errInvalidEnvOverride = errors.New("invalid environment variable override")
+	errInvalidEnvPrefix   = errors.New("invalid environment variable prefix")
+	errEnvCollision       = errors.New("environment variable collision")
+)
+
+// EnvName returns the name of the environment variable that Reginald reads
+// the value of the given ConfigEntry from. The command is the name of
+// the command that the ConfigEntry belongs to, or the empty string for
+// the plugin-level config.
+//
+// If the ConfigEntry has an EnvOverride, the name is the EnvOverride after
+// [EnvNamePrefix] and neither the domain nor the command is added to it.
+// Otherwise, the name is composed of EnvNamePrefix, the EnvPrefix of
+// the manifest or the domain of the plugin if EnvPrefix is not set, the command
+// if it is not empty, and the key of the ConfigEntry, separated by
+// underscores. For example, the key "target" of the command "build" in
+// the plugin "myplugin" is read from REGINALD_MYPLUGIN_BUILD_TARGET. The parts
+// are converted to uppercase and the characters other than ASCII letters and
+// digits are replaced with underscores.
+//
+// The entries of a command are always read from the variables scoped to
+// the command. If a plugin-level entry and a command entry have the same key,
+// or the command entry inherits the plugin-level entry, the command reads
+// the value only from its own variable and the plugin-level variable applies
+// to the plugin-level config. The exception is an inherited EnvOverride: as
+// the name does not include the command, the entries share the variable.
+// [Manifest.Validate] rejects manifests in which two entries resolve to
+// the same variable, for example, the plugin-level key "build-target" and
+// the key "target" of the command "build".
+//
+// EnvName returns the empty string for a ConfigEntry with FlagOnly set as
+// it is not read from the environment. For inheriting command config,
+// the entry should be resolved with [Manifest.ResolveEntry] first.
+func (m *Manifest) EnvName(command string, e ConfigEntry) string {
+	if e.FlagOnly {
+		return ""
+	}
+
+	if e.EnvOverride != "" {
+		return EnvNamePrefix + e.EnvOverride
+	}
+
+	var sb strings.Builder
+
+	sb.WriteString(EnvNamePrefix)
+
+	if m.EnvPrefix != "" {
+		sb.WriteString(m.EnvPrefix)
+	} else {
+		sb.WriteString(envFragment(m.Domain))
+	}
+
+	sb.WriteByte('_')
+
+	if command != "" {
+		sb.WriteString(envFragment(command))
+		sb.WriteByte('_')
+	}
+
+	sb.WriteString(envFragment(e.Key))
+
+	return sb.String()
+}
+
+// EnvNames returns the names of the environment variables that Reginald reads
+// the config values of the given command from, keyed by the keys of
+// the ConfigEntries. If command is empty, the names are for the plugin-level
+// Config. The names include the GlobalConfig of the plugin in both cases. The
+// entries with FlagOnly set are omitted. It returns an error if the command
+// does not exist or if its config cannot be resolved. See [Manifest.EnvName].
+func (m *Manifest) EnvNames(command string) (map[string]string, error) {
+	names := make(map[string]string)
+
+	add := func(command string, entries []ConfigEntry) {
+		for _, e := range entries {
+			if name := m.EnvName(command, e); name != "" {
+				names[e.Key] = name
+			}
+		}
+	}
+
+	add("", m.GlobalConfig)
+
+	if command == "" {
+		add("", m.Config)
+
+		return names, nil
+	}
+
+	c, ok := m.LookupCommand(command)
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", errCommandNotFound, command)
+	}
+
+	for _, e := range c.Config {
+		resolved, err := m.ResolveEntry(e)
+		if err != nil {
+			return nil, fmt.Errorf("command %q: %w", c.Name, err)
+		}
+
+		add(c.Name, []ConfigEntry{resolved})
+	}
+
+	return names, nil
+}
+
+// checkEnvCollisions checks that no two config entries of the plugin are read
+// from the same environment variable, whether the names are computed or set
+// with EnvOverride. As only one command is run at a time, the entries of
+// a command are checked against the plugin-level entries and the other entries
+// of the same command but not against the entries of the other commands.
+// The entries of the commands must be resolvable.
+func (m *Manifest) checkEnvCollisions() error {
+	type use struct {
+		path      string
+		key       string
+		pluginKey string // key of a plugin-level Config entry
+	}
+
+	check := func(uses map[string]use, u use, command string, e ConfigEntry) error {
+		name := m.EnvName(command, e)
+		if name == "" {
+			return nil
+		}
+
+		if other, ok := uses[name]; ok {
+			// An entry that inherits the EnvOverride of a plugin-level entry
+			// shares the variable on purpose.
+			if e.Inherit != "" && e.Inherit == other.pluginKey {
+				return nil
+			}
+
+			return fmt.Errorf(
+				"%s: %w: %s of %q is also used by %q at %s",
+				u.path,
+				errEnvCollision,
+				name,
+				u.key,
+				other.key,
+				other.path,
+			)
+		}
+
+		uses[name] = u
+
+		return nil
+	}
+
+	plugin := make(map[string]use)
+
+	for i, e := range m.Config {
+		if err := check(
+			plugin,
+			use{fmt.Sprintf("config[%d]", i), e.Key, e.Key},
+			"",
+			e,
+		); err != nil {
+			return err
+		}
+	}
+
+	for i, e := range m.GlobalConfig {
+		if err := check(
+			plugin,
+			use{fmt.Sprintf("globalConfig[%d]", i), e.Key, ""},
+			"",
+			e,
+		); err != nil {
+			return err
+		}
+	}
+
+	for i, c := range m.Commands {
+		uses := maps.Clone(plugin)
+
+		for j, e := range c.Config {
+			resolved, err := m.ResolveEntry(e)
+			if err != nil {
+				return fmt.Errorf("commands[%d].config[%d]: %w", i, j, err)
+			}
+
+			resolved.Inherit = e.Inherit
+
+			u := use{fmt.Sprintf("commands[%d].config[%d]", i, j), resolved.Key, ""}
+			if err := check(uses, u, c.Name, resolved); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// envFragment converts s to a form that can be used as a part of
+// an environment variable name.
+func envFragment(s string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z':
+			return r - 'a' + 'A'
+		case (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9'):
+			return r
+		default:
+			return '_'
+		}
+	}, s)
+}
+
+// validateEnvFragment checks that s can be used as a part of an environment
+// variable name. The returned error wraps errBase and suggests a valid name.
+func validateEnvFragment(s string, errBase error) error {
+	valid := s != "" && (s[0] < '0' || s[0] > '9')
+
+	for _, r := range s {
+		if (r < 'A' || r > 'Z') && (r < '0' || r > '9') && r != '_' {
+			valid = false
+
+			break
+		}
+	}
+
+	if valid {
+		return nil
+	}
+
+	suggestion := envFragment(s)
+	if suggestion == "" || (suggestion[0] >= '0' && suggestion[0] <= '9') {
+		suggestion = "_" + suggestion
+	}
+
+	return fmt.Errorf("%w: %q, did you mean %q?", errBase, s, suggestion)
+}
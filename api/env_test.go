@@ -0,0 +1,331 @@
+// Copyright 2025 Antti Kivi
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api_test
+
+import (
+	"maps"
+	"strings"
+	"testing"
+
+	"github.com/reginald-project/reginald-sdk-go/api"
+)
+
+func TestManifestEnvName(t *testing.T) {
+	t.Parallel()
+
+	for _, test := range []struct {
+		domain  string
+		command string
+		entry   api.ConfigEntry
+		want    string
+	}{
+		{
+			"myplugin",
+			"",
+			api.ConfigEntry{KeyValue: api.KeyValue{Key: "target"}},
+			"REGINALD_MYPLUGIN_TARGET",
+		},
+		{
+			"myplugin",
+			"build",
+			api.ConfigEntry{KeyValue: api.KeyValue{Key: "target"}},
+			"REGINALD_MYPLUGIN_BUILD_TARGET",
+		},
+		{
+			"my-plugin",
+			"build-all",
+			api.ConfigEntry{KeyValue: api.KeyValue{Key: "output.dir"}},
+			"REGINALD_MY_PLUGIN_BUILD_ALL_OUTPUT_DIR",
+		},
+		{
+			"myplugin",
+			"",
+			api.ConfigEntry{KeyValue: api.KeyValue{Key: "target"}, EnvOverride: "TARGET"},
+			"REGINALD_TARGET",
+		},
+		{
+			"myplugin",
+			"build",
+			api.ConfigEntry{KeyValue: api.KeyValue{Key: "target"}, EnvOverride: "BUILD_DEST"},
+			"REGINALD_BUILD_DEST",
+		},
+		{
+			"myplugin",
+			"build",
+			api.ConfigEntry{KeyValue: api.KeyValue{Key: "target"}, FlagOnly: true},
+			"",
+		},
+	} {
+		m := &api.Manifest{Name: "Example", Domain: test.domain}
+
+		if got := m.EnvName(test.command, test.entry); got != test.want {
+			t.Errorf(
+				"%q, %q, %#v: got %q, want %q",
+				test.domain,
+				test.command,
+				test.entry,
+				got,
+				test.want,
+			)
+		}
+	}
+}
+
+func TestManifestEnvPrefix(t *testing.T) {
+	t.Parallel()
+
+	m := &api.Manifest{
+		Name:      "Example",
+		Domain:    "my-long-plugin-domain",
+		EnvPrefix: "MLP",
+		Config: []api.ConfigEntry{
+			{KeyValue: api.KeyValue{Key: "token", Type: api.StringValue}},
+		},
+		GlobalConfig: []api.ConfigEntry{
+			{KeyValue: api.KeyValue{Key: "color", Type: api.BoolValue}},
+		},
+		Commands: []api.Command{
+			{
+				Name: "build",
+				Config: []api.ConfigEntry{
+					{KeyValue: api.KeyValue{Key: "target", Type: api.StringValue}},
+					{
+						KeyValue:    api.KeyValue{Key: "out", Type: api.StringValue},
+						EnvOverride: "OUT_DIR",
+					},
+					{
+						KeyValue: api.KeyValue{Key: "dry-run", Type: api.BoolValue},
+						Flag:     &api.Flag{},
+						FlagOnly: true,
+					},
+				},
+			},
+		},
+	}
+
+	if err := m.Validate(); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, test := range []struct {
+		command string
+		want    map[string]string
+	}{
+		{"", map[string]string{"color": "REGINALD_MLP_COLOR", "token": "REGINALD_MLP_TOKEN"}},
+		{
+			"build",
+			map[string]string{
+				"color":  "REGINALD_MLP_COLOR",
+				"target": "REGINALD_MLP_BUILD_TARGET",
+				"out":    "REGINALD_OUT_DIR",
+			},
+		},
+	} {
+		got, err := m.EnvNames(test.command)
+		if err != nil {
+			t.Fatalf("%q: %v", test.command, err)
+		}
+
+		if !maps.Equal(got, test.want) {
+			t.Errorf("%q: got %v, want %v", test.command, got, test.want)
+		}
+	}
+
+	_, err := m.EnvNames("missing")
+	if err == nil || !strings.Contains(err.Error(), "command not found") {
+		t.Errorf("got %v, want string containing %q", err, "command not found")
+	}
+
+	m.EnvPrefix = "my-lp"
+	want := `envPrefix: invalid environment variable prefix: "my-lp", did you mean "MY_LP"?`
+	if err := m.Validate(); err == nil || !strings.Contains(err.Error(), want) {
+		t.Errorf("got %v, want string containing %q", err, want)
+	}
+}
+
+func TestManifestValidateEnvOverride(t *testing.T) {
+	t.Parallel()
+
+	for _, test := range []struct {
+		override string
+		want     string // error string should contain this, empty for no error
+	}{
+		{"MY_VAR2", ""},
+		{"_PRIVATE", ""},
+		{"MY-VAR", `invalid environment variable override: "MY-VAR", did you mean "MY_VAR"?`},
+		{"my var", `did you mean "MY_VAR"?`},
+		{"2FA", `did you mean "_2FA"?`},
+	} {
+		m := &api.Manifest{
+			Name:   "Example",
+			Domain: "example",
+			Config: []api.ConfigEntry{
+				{
+					KeyValue:    api.KeyValue{Key: "a", Type: api.StringValue},
+					EnvOverride: test.override,
+				},
+			},
+		}
+
+		err := m.Validate()
+		if test.want == "" {
+			if err != nil {
+				t.Errorf("%q: unexpected error: %v", test.override, err)
+			}
+
+			continue
+		}
+
+		if err == nil || !strings.Contains(err.Error(), test.want) {
+			t.Errorf("%q: got %v, want string containing %q", test.override, err, test.want)
+		}
+	}
+}
+
+func TestManifestValidateEnvCollision(t *testing.T) {
+	t.Parallel()
+
+	str := func(key string) api.ConfigEntry {
+		return api.ConfigEntry{KeyValue: api.KeyValue{Key: key, Type: api.StringValue}}
+	}
+
+	for _, test := range []struct {
+		m    api.Manifest
+		want string // error string should contain this, empty for no error
+	}{
+		{
+			api.Manifest{
+				Config:   []api.ConfigEntry{str("target")},
+				Commands: []api.Command{{Name: "build", Config: []api.ConfigEntry{str("target")}}},
+			},
+			"",
+		},
+		{
+			api.Manifest{
+				Config:   []api.ConfigEntry{str("build-target")},
+				Commands: []api.Command{{Name: "build", Config: []api.ConfigEntry{str("target")}}},
+			},
+			"commands[0].config[0]: environment variable collision: " +
+				"REGINALD_EXAMPLE_BUILD_TARGET of \"target\" is also used by \"build-target\" " +
+				"at config[0]",
+		},
+		{
+			api.Manifest{Config: []api.ConfigEntry{str("out-dir"), str("out_dir")}},
+			`config[1]: environment variable collision: ` +
+				`REGINALD_EXAMPLE_OUT_DIR of "out_dir" is also used by "out-dir" at config[0]`,
+		},
+		{
+			api.Manifest{
+				GlobalConfig: []api.ConfigEntry{str("token")},
+				Commands: []api.Command{
+					{
+						Name: "a",
+						Config: []api.ConfigEntry{
+							{KeyValue: str("b").KeyValue, EnvOverride: "EXAMPLE_TOKEN"},
+						},
+					},
+				},
+			},
+			`commands[0].config[0]: environment variable collision: ` +
+				`REGINALD_EXAMPLE_TOKEN of "b" is also used by "token" at globalConfig[0]`,
+		},
+		{
+			api.Manifest{
+				Config: []api.ConfigEntry{
+					{KeyValue: str("user").KeyValue, EnvOverride: "USER"},
+					{KeyValue: str("login").KeyValue, EnvOverride: "USER"},
+				},
+			},
+			`config[1]: environment variable collision: ` +
+				`REGINALD_USER of "login" is also used by "user" at config[0]`,
+		},
+		{
+			api.Manifest{
+				Config: []api.ConfigEntry{{KeyValue: str("user").KeyValue, EnvOverride: "USER"}},
+				Commands: []api.Command{
+					{
+						Name: "a",
+						Config: []api.ConfigEntry{
+							{KeyValue: str("login").KeyValue, EnvOverride: "USER"},
+						},
+					},
+				},
+			},
+			`commands[0].config[0]: environment variable collision: ` +
+				`REGINALD_USER of "login" is also used by "user" at config[0]`,
+		},
+		{
+			api.Manifest{
+				Commands: []api.Command{
+					{
+						Name: "a",
+						Config: []api.ConfigEntry{
+							{KeyValue: str("user").KeyValue, EnvOverride: "USER"},
+						},
+					},
+					{
+						Name: "b",
+						Config: []api.ConfigEntry{
+							{KeyValue: str("login").KeyValue, EnvOverride: "USER"},
+						},
+					},
+				},
+			},
+			"",
+		},
+		{
+			api.Manifest{
+				Commands: []api.Command{
+					{
+						Name: "a",
+						Config: []api.ConfigEntry{
+							{KeyValue: str("user").KeyValue, EnvOverride: "USER"},
+							{KeyValue: str("login").KeyValue, EnvOverride: "USER"},
+						},
+					},
+				},
+			},
+			`commands[0].config[1]: environment variable collision: ` +
+				`REGINALD_USER of "login" is also used by "user" at commands[0].config[0]`,
+		},
+		{
+			api.Manifest{
+				Config: []api.ConfigEntry{
+					{KeyValue: str("target").KeyValue, EnvOverride: "TARGET"},
+				},
+				Commands: []api.Command{
+					{Name: "build", Config: []api.ConfigEntry{{Inherit: "target"}}},
+				},
+			},
+			"",
+		},
+	} {
+		m := test.m
+		m.Name, m.Domain = "Example", "example"
+
+		err := m.Validate()
+		if test.want == "" {
+			if err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+
+			continue
+		}
+
+		if err == nil || !strings.Contains(err.Error(), test.want) {
+			t.Errorf("got %v, want string containing %q", err, test.want)
+		}
+	}
+}
@@ -0,0 +1,127 @@
+// Copyright 2025 Antti Kivi
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// The standard codes of the errors that a plugin reports with [WriteError].
+const (
+	// CodeConfigInvalid means that the config given to the plugin is not
+	// valid, for example, a value is out of range or a required value is
+	// missing.
+	CodeConfigInvalid ErrorCode = "config-invalid"
+
+	// CodeNotSupported means that the plugin does not support the requested
+	// operation, for example, a task type or a platform.
+	CodeNotSupported ErrorCode = "not-supported"
+
+	// CodeInternal means that the plugin failed for a reason that is not
+	// the fault of the user or the config. It is the code of the errors that
+	// have no code.
+	CodeInternal ErrorCode = "internal"
+)
+
+// An ErrorCode identifies the kind of an Error so that Reginald can, for
+// example, present the failures of all of the plugins uniformly.
+type ErrorCode string
+
+// An Error is a failure of a plugin in a machine-readable form that Reginald
+// can parse. The plugin writes it with [WriteError].
+type Error struct {
+	// Code identifies the kind of the failure. It should be one of
+	// the standard codes, like [CodeConfigInvalid]. An empty Code is
+	// encoded as [CodeInternal].
+	Code ErrorCode `json:"code"`
+
+	// Message describes the failure to the user.
+	Message string `json:"message"`
+
+	// Details is optional additional data about the failure, for example,
+	// the key of the invalid config entry. It must be encodable as JSON.
+	Details any `json:"details,omitempty"`
+}
+
+// Error returns the code and the message of the Error.
+func (e *Error) Error() string {
+	return string(e.code()) + ": " + e.Message
+}
+
+// MarshalJSON implements [encoding/json.Marshaler]. It encodes the Error as
+// a JSON object with "code", "message", and, if it is set, "details". An empty
+// Code is encoded as [CodeInternal]. The characters special to HTML are not
+// escaped.
+func (e *Error) MarshalJSON() ([]byte, error) {
+	type jsonError Error
+
+	v := jsonError(*e)
+	v.Code = e.code()
+
+	var buf bytes.Buffer
+
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+
+	if err := enc.Encode(v); err != nil {
+		return nil, fmt.Errorf("failed to marshal error: %w", err)
+	}
+
+	return bytes.TrimSuffix(buf.Bytes(), []byte("\n")), nil
+}
+
+// WriteError writes err to w as a single line of JSON in the format of
+// [Error.MarshalJSON] so that Reginald can parse it. If err is or wraps
+// an *Error, that Error is written. Otherwise, err is written as an Error with
+// [CodeInternal] and the text of err as the message. If err is nil, nothing is
+// written.
+func WriteError(w io.Writer, err error) error {
+	if err == nil {
+		return nil
+	}
+
+	var e *Error
+	if !errors.As(err, &e) {
+		e = &Error{Code: CodeInternal, Message: err.Error(), Details: nil}
+	}
+
+	var buf bytes.Buffer
+
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+
+	if err := enc.Encode(e); err != nil {
+		return fmt.Errorf("failed to encode error: %w", err)
+	}
+
+	if _, err := w.Write(buf.Bytes()); err != nil {
+		return fmt.Errorf("failed to write error: %w", err)
+	}
+
+	return nil
+}
+
+// code returns the Code of e, or [CodeInternal] if the Code is empty.
+func (e *Error) code() ErrorCode {
+	if e.Code == "" {
+		return CodeInternal
+	}
+
+	return e.Code
+}
@@ -0,0 +1,96 @@
+// Copyright 2025 Antti Kivi
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api_test
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/reginald-project/reginald-sdk-go/api"
+)
+
+func TestWriteError(t *testing.T) {
+	t.Parallel()
+
+	for _, test := range []struct {
+		err  error
+		want string
+	}{
+		{nil, ""},
+		{errors.New("disk full"), `{"code":"internal","message":"disk full"}`},
+		{
+			&api.Error{
+				Code:    api.CodeConfigInvalid,
+				Message: "jobs must be <= 8",
+				Details: map[string]any{"key": "jobs"},
+			},
+			`{"code":"config-invalid","message":"jobs must be <= 8","details":{"key":"jobs"}}`,
+		},
+		{
+			fmt.Errorf(
+				"task %q: %w",
+				"link",
+				&api.Error{Code: api.CodeNotSupported, Message: "no symlinks"},
+			),
+			`{"code":"not-supported","message":"no symlinks"}`,
+		},
+		{&api.Error{Message: "boom"}, `{"code":"internal","message":"boom"}`},
+	} {
+		var buf bytes.Buffer
+
+		if err := api.WriteError(&buf, test.err); err != nil {
+			t.Fatalf("%v: unexpected error: %v", test.err, err)
+		}
+
+		want := test.want
+		if want != "" {
+			want += "\n"
+		}
+
+		if got := buf.String(); got != want {
+			t.Errorf("%v: got %q, want %q", test.err, got, want)
+		}
+	}
+}
+
+func TestWriteErrorDetails(t *testing.T) {
+	t.Parallel()
+
+	err := api.WriteError(
+		&bytes.Buffer{},
+		&api.Error{Code: api.CodeInternal, Message: "x", Details: make(chan int)},
+	)
+	if err == nil || !strings.Contains(err.Error(), "failed to encode error") {
+		t.Errorf("got %v, want string containing %q", err, "failed to encode error")
+	}
+}
+
+func TestError(t *testing.T) {
+	t.Parallel()
+
+	err := fmt.Errorf("wrapped: %w", &api.Error{Code: api.CodeConfigInvalid, Message: "bad value"})
+
+	if got, want := err.Error(), "wrapped: config-invalid: bad value"; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+
+	var e *api.Error
+	if !errors.As(err, &e) || e.Code != api.CodeConfigInvalid {
+		t.Errorf("got %v, want an *api.Error with %s", e, api.CodeConfigInvalid)
+	}
+}
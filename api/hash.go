@@ -0,0 +1,149 @@
+// Copyright 2025 Antti Kivi
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"cmp"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"slices"
+)
+
+// Hash returns a hex-encoded SHA-256 hash of the manifest that can be used for
+// detecting changes in it, for example, as a cache key. Semantically equal
+// manifests have the same hash: the values are hashed according to their Type
+// and not according to their Go types, so an [IntValue] decoded from JSON as
+// float64 hashes the same as one with an int value. The order of
+// the capabilities, the aliases, the tasks, the inputs and outputs of tasks,
+// the flags within the mutually exclusive groups and the required flags of
+// a command does not affect the hash. The order of the commands and the config
+// entries does, as it is shown to the user.
+func (m *Manifest) Hash() (string, error) {
+	data, err := json.Marshal(m.canonical())
+	if err != nil {
+		return "", fmt.Errorf("failed to encode manifest for hashing: %w", err)
+	}
+
+	sum := sha256.Sum256(data)
+
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// canonical returns a copy of m in the canonical form used by Hash. The order
+// insensitive collections are sorted and the values are normalized.
+func (m *Manifest) canonical() *Manifest {
+	c := *m
+	c.Config = canonicalEntries(m.Config)
+	c.GlobalConfig = canonicalEntries(m.GlobalConfig)
+	c.Capabilities = sortedSet(m.Capabilities)
+	c.Commands = make([]Command, len(m.Commands))
+	c.Tasks = make([]Task, len(m.Tasks))
+
+	for i, cmd := range m.Commands {
+		cmd.Aliases = sortedSet(cmd.Aliases)
+		cmd.Config = canonicalEntries(cmd.Config)
+
+		groups := make([][]string, len(cmd.MutexGroups))
+		for j, g := range cmd.MutexGroups {
+			groups[j] = sortedSet(g)
+		}
+
+		cmd.MutexGroups = groups
+
+		if cmd.Requires != nil {
+			requires := make(map[string][]string, len(cmd.Requires))
+			for k, v := range cmd.Requires {
+				requires[k] = sortedSet(v)
+			}
+
+			cmd.Requires = requires
+		}
+
+		c.Commands[i] = cmd
+	}
+
+	for i, t := range m.Tasks {
+		t.Aliases = sortedSet(t.Aliases)
+		t.Config = canonicalEntries(t.Config)
+		t.Inputs = sortedSet(t.Inputs)
+		t.Outputs = sortedSet(t.Outputs)
+		c.Tasks[i] = t
+	}
+
+	slices.SortFunc(c.Tasks, func(a, b Task) int { return cmp.Compare(a.Type, b.Type) })
+
+	return &c
+}
+
+// canonicalEntries returns copies of the entries with their values
+// normalized.
+func canonicalEntries(entries []ConfigEntry) []ConfigEntry {
+	if entries == nil {
+		return nil
+	}
+
+	result := make([]ConfigEntry, len(entries))
+
+	for i, e := range entries {
+		e.KeyValue = canonicalKeyValue(e.KeyValue)
+
+		if e.AllowedValues != nil {
+			allowed := make([]any, len(e.AllowedValues))
+			for j, a := range e.AllowedValues {
+				allowed[j] = KeyValue{
+					Key:    e.Key,
+					Value:  a,
+					Type:   e.Type,
+					Fields: e.Fields,
+				}.normalizedValue()
+			}
+
+			e.AllowedValues = allowed
+		}
+
+		result[i] = e
+	}
+
+	return result
+}
+
+// canonicalKeyValue returns a copy of kv with its value and the defaults of
+// its fields normalized.
+func canonicalKeyValue(kv KeyValue) KeyValue {
+	kv.Value = kv.normalizedValue()
+
+	if kv.Fields != nil {
+		fields := make([]KeyValue, len(kv.Fields))
+		for i, f := range kv.Fields {
+			fields[i] = canonicalKeyValue(f)
+		}
+
+		kv.Fields = fields
+	}
+
+	return kv
+}
+
+// sortedSet returns the unique strings in s in sorted order, or nil if s is
+// empty.
+func sortedSet(s []string) []string {
+	if len(s) == 0 {
+		return nil
+	}
+
+	return slices.Compact(slices.Sorted(slices.Values(s)))
+}
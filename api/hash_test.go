@@ -0,0 +1,116 @@
+// Copyright 2025 Antti Kivi
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api_test
+
+import (
+	"encoding/json"
+	"reflect"
+	"slices"
+	"testing"
+
+	"github.com/reginald-project/reginald-sdk-go/api"
+)
+
+func hash(t *testing.T, m *api.Manifest) string {
+	t.Helper()
+
+	h, err := m.Hash()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return h
+}
+
+func TestManifestHashStable(t *testing.T) {
+	t.Parallel()
+
+	m := fullManifest()
+	m.Capabilities = []string{api.CapabilityExec, api.CapabilityFSWrite}
+	want := hash(t, m)
+
+	if len(want) != 64 {
+		t.Errorf("got hash %q, want 64 hex digits", want)
+	}
+
+	data, err := m.MarshalIndent()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var decoded api.Manifest
+
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := hash(t, &decoded); got != want {
+		t.Errorf("re-encoded: got %s, want %s", got, want)
+	}
+
+	reordered := fullManifest()
+	reordered.Capabilities = []string{api.CapabilityFSWrite, api.CapabilityExec}
+	slices.Reverse(reordered.Tasks)
+
+	for i := range reordered.Tasks {
+		slices.Reverse(reordered.Tasks[i].Aliases)
+	}
+
+	for i := range reordered.Commands {
+		slices.Reverse(reordered.Commands[i].Aliases)
+	}
+
+	if got := hash(t, reordered); got != want {
+		t.Errorf("reordered: got %s, want %s", got, want)
+	}
+
+	if !slices.Equal(m.Capabilities, []string{api.CapabilityExec, api.CapabilityFSWrite}) ||
+		!reflect.DeepEqual(m.Tasks, fullManifest().Tasks) {
+		t.Error("Hash modified the manifest")
+	}
+}
+
+func TestManifestHashValues(t *testing.T) {
+	t.Parallel()
+
+	manifest := func(v any) *api.Manifest {
+		return &api.Manifest{
+			Name:   "Example",
+			Domain: "example",
+			Config: []api.ConfigEntry{
+				{
+					KeyValue:      api.KeyValue{Key: "n", Value: v, Type: api.IntValue},
+					AllowedValues: []any{v, 2.0},
+				},
+			},
+		}
+	}
+
+	if a, b := hash(t, manifest(1)), hash(t, manifest(1.0)); a != b {
+		t.Errorf("int and float64 values hash differently: %s and %s", a, b)
+	}
+
+	if a, b := hash(t, manifest(1)), hash(t, manifest(3)); a == b {
+		t.Errorf("different values hash the same: %s", a)
+	}
+
+	m := manifest(1)
+	before := hash(t, m)
+	m.Commands = []api.Command{{Name: "run"}}
+
+	if after := hash(t, m); after == before {
+		t.Error("adding a command did not change the hash")
+	}
+}
@@ -0,0 +1,160 @@
+// Copyright 2025 Antti Kivi
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import "fmt"
+
+// The codes of the warnings returned by [Manifest.Lint].
+const (
+	// WarnFlagOnlyEnvOverride is reported for a ConfigEntry that sets both
+	// FlagOnly and EnvOverride. The EnvOverride is ignored as the value of
+	// a flag-only entry is not read from the environment.
+	WarnFlagOnlyEnvOverride WarningCode = "flag-only-env-override"
+
+	// WarnMissingCommandDescription is reported for a Command without
+	// a Description.
+	WarnMissingCommandDescription WarningCode = "missing-command-description"
+
+	// WarnMissingFlagDescription is reported for a Flag without a Description.
+	WarnMissingFlagDescription WarningCode = "missing-flag-description"
+
+	// WarnHelpShorthand is reported for a Flag with the shorthand "h" that is
+	// conventionally used for the help flag.
+	WarnHelpShorthand WarningCode = "help-shorthand"
+
+	// WarnUsageDomain is reported for a Command whose Usage starts with
+	// the plugin domain. Reginald adds the domain itself, so it would be shown
+	// twice in the help message. See [Command.NormalizedUsage].
+	WarnUsageDomain WarningCode = "usage-domain"
+)
+
+// A WarningCode identifies the kind of a Warning so that tools can, for
+// example, filter the warnings.
+type WarningCode string
+
+// A Warning is a problem in a manifest that does not make it invalid but that
+// is likely a mistake.
+type Warning struct {
+	// Code identifies the kind of the problem.
+	Code WarningCode
+
+	// Path is the path of the offending field within the manifest in the same
+	// format as in the errors returned by [Manifest.Validate], for example,
+	// "commands[0].config[1]".
+	Path string
+
+	// Message describes the problem.
+	Message string
+}
+
+// Lint checks the manifest for likely mistakes that [Manifest.Validate] does
+// not reject and returns a Warning for each of them. The warnings are in
+// the order of the fields in the manifest. Lint does not validate the manifest
+// and it should be called in addition to Validate. The possible warning codes
+// are:
+//
+//   - [WarnFlagOnlyEnvOverride]
+//   - [WarnMissingCommandDescription]
+//   - [WarnMissingFlagDescription]
+//   - [WarnHelpShorthand]
+//   - [WarnUsageDomain]
+func (m *Manifest) Lint() []Warning {
+	var warnings []Warning
+
+	lintEntries := func(path string, entries []ConfigEntry) {
+		for i, e := range entries {
+			warnings = append(warnings, lintConfigEntry(fmt.Sprintf("%s[%d]", path, i), e)...)
+		}
+	}
+
+	lintEntries("config", m.Config)
+	lintEntries("globalConfig", m.GlobalConfig)
+
+	for i, c := range m.Commands {
+		if c.Description == "" {
+			warnings = append(warnings, Warning{
+				Code:    WarnMissingCommandDescription,
+				Path:    fmt.Sprintf("commands[%d]", i),
+				Message: fmt.Sprintf("command %q has no description", c.Name),
+			})
+		}
+
+		if _, ok := trimUsageDomain(c.Usage, m.Domain); ok {
+			warnings = append(warnings, Warning{
+				Code: WarnUsageDomain,
+				Path: fmt.Sprintf("commands[%d].usage", i),
+				Message: fmt.Sprintf(
+					"the usage of command %q includes the domain %q that Reginald adds",
+					c.Name,
+					m.Domain,
+				),
+			})
+		}
+
+		lintEntries(fmt.Sprintf("commands[%d].config", i), c.Config)
+	}
+
+	for i, t := range m.Tasks {
+		lintEntries(fmt.Sprintf("tasks[%d].config", i), t.Config)
+	}
+
+	return warnings
+}
+
+// String returns the warning as a single line.
+func (w Warning) String() string {
+	return fmt.Sprintf("%s: %s [%s]", w.Path, w.Message, w.Code)
+}
+
+// lintConfigEntry returns the warnings for a single config entry at path.
+func lintConfigEntry(path string, e ConfigEntry) []Warning {
+	var warnings []Warning
+
+	if e.FlagOnly && e.EnvOverride != "" {
+		warnings = append(warnings, Warning{
+			Code: WarnFlagOnlyEnvOverride,
+			Path: path,
+			Message: fmt.Sprintf(
+				"key %q is flag-only, so envOverride %q is ignored as the value is not read "+
+					"from the environment",
+				e.Key,
+				e.EnvOverride,
+			),
+		})
+	}
+
+	// The inheriting entries may get the description from the inherited
+	// flag.
+	if e.Flag != nil && e.Flag.Description == "" && e.Description == "" && e.Inherit == "" {
+		warnings = append(warnings, Warning{
+			Code:    WarnMissingFlagDescription,
+			Path:    path + ".flag",
+			Message: fmt.Sprintf("the flag of key %q has no description", e.Key),
+		})
+	}
+
+	if e.Flag != nil && e.Flag.Shorthand == "h" {
+		warnings = append(warnings, Warning{
+			Code: WarnHelpShorthand,
+			Path: path + ".flag.shorthand",
+			Message: fmt.Sprintf(
+				"the flag of key %q uses the shorthand -h that is conventionally used for help",
+				e.Key,
+			),
+		})
+	}
+
+	return warnings
+}
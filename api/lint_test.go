@@ -0,0 +1,133 @@
+// Copyright 2025 Antti Kivi
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/reginald-project/reginald-sdk-go/api"
+)
+
+func TestManifestLintFlagOnlyEnvOverride(t *testing.T) {
+	t.Parallel()
+
+	m := &api.Manifest{
+		Name:   "Example",
+		Domain: "example",
+		Config: []api.ConfigEntry{
+			{
+				KeyValue: api.KeyValue{Key: "plain", Type: api.BoolValue},
+				Flag:     &api.Flag{Description: "Plain."},
+				FlagOnly: true,
+			},
+		},
+		Commands: []api.Command{
+			{
+				Name:        "run",
+				Description: "Run.",
+				Config: []api.ConfigEntry{
+					{KeyValue: api.KeyValue{Key: "ok", Type: api.BoolValue}, EnvOverride: "OK"},
+					{
+						KeyValue:    api.KeyValue{Key: "dry-run", Type: api.BoolValue},
+						Flag:        &api.Flag{Description: "Dry run."},
+						FlagOnly:    true,
+						EnvOverride: "DRY_RUN",
+					},
+				},
+			},
+		},
+	}
+
+	if err := m.Validate(); err != nil {
+		t.Fatalf("manifest should be valid: %v", err)
+	}
+
+	warnings := m.Lint()
+	if len(warnings) != 1 {
+		t.Fatalf("got %d warnings, want 1: %v", len(warnings), warnings)
+	}
+
+	if got, want := warnings[0].Path, "commands[0].config[1]"; got != want {
+		t.Errorf("got path %q, want %q", got, want)
+	}
+
+	if got, want := warnings[0].Code, api.WarnFlagOnlyEnvOverride; got != want {
+		t.Errorf("got code %q, want %q", got, want)
+	}
+
+	want := `commands[0].config[1]: key "dry-run" is flag-only, so envOverride "DRY_RUN" is ignored`
+	if got := warnings[0].String(); !strings.Contains(got, want) {
+		t.Errorf("got %q, want string containing %q", got, want)
+	}
+}
+
+func TestManifestLint(t *testing.T) {
+	t.Parallel()
+
+	m := &api.Manifest{
+		Name:   "Example",
+		Domain: "example",
+		Config: []api.ConfigEntry{
+			{
+				KeyValue: api.KeyValue{Key: "host", Type: api.StringValue},
+				Flag:     &api.Flag{Shorthand: "h"},
+			},
+			{
+				KeyValue:    api.KeyValue{Key: "port", Type: api.IntValue},
+				Description: "Port.",
+				Flag:        &api.Flag{},
+			},
+		},
+		Commands: []api.Command{
+			{
+				Name: "run",
+				Config: []api.ConfigEntry{
+					{KeyValue: api.KeyValue{Key: "host"}, Inherit: "host", Flag: &api.Flag{}},
+				},
+			},
+			{Name: "show", Description: "Show things."},
+			{Name: "list", Usage: "example list [flags]", Description: "List things."},
+			{Name: "get", Usage: "examples get", Description: "Get things."},
+		},
+	}
+
+	if err := m.Validate(); err != nil {
+		t.Fatalf("manifest should be valid: %v", err)
+	}
+
+	want := []api.Warning{
+		{Code: api.WarnMissingFlagDescription, Path: "config[0].flag"},
+		{Code: api.WarnHelpShorthand, Path: "config[0].flag.shorthand"},
+		{Code: api.WarnMissingCommandDescription, Path: "commands[0]"},
+		{Code: api.WarnUsageDomain, Path: "commands[2].usage"},
+	}
+
+	got := m.Lint()
+	if len(got) != len(want) {
+		t.Fatalf("got %v, want %d warnings", got, len(want))
+	}
+
+	for i, w := range got {
+		if w.Code != want[i].Code || w.Path != want[i].Path || w.Message == "" {
+			t.Errorf("[%d]: got %v, want code %q at %s", i, w, want[i].Code, want[i].Path)
+		}
+	}
+
+	want0 := `commands[0]: command "run" has no description [missing-command-description]`
+	if s := got[2].String(); s != want0 {
+		t.Errorf("got %q, want %q", s, want0)
+	}
+}
@@ -14,11 +14,43 @@
 
 package api
 
-// The supported value types for a KeyValue.
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"maps"
+	"slices"
+	"strings"
+	"sync/atomic"
+
+	"github.com/reginald-project/reginald-sdk-go/logs"
+)
+
+// The supported value types for a KeyValue. An [IntValue] is a 64-bit signed
+// integer and a [UintValue] is a 64-bit unsigned integer; see
+// [KeyValue.Int64] and [KeyValue.Uint]. A [PathValue] holds a string that
+// is a path in the file system. It is handled like a [StringValue] but it
+// tells Reginald that the value can be completed as a file path.
 const (
 	BoolValue   ValueType = "bool"
 	IntValue    ValueType = "int"
+	UintValue   ValueType = "uint"
 	StringValue ValueType = "string"
+	PathValue   ValueType = "path"
+	ObjectValue ValueType = "object"
+)
+
+// The capabilities that a plugin can declare in its manifest.
+const (
+	// CapabilityExec means that the plugin spawns subprocesses.
+	CapabilityExec = "exec"
+
+	// CapabilityFSWrite means that the plugin writes to the file system.
+	CapabilityFSWrite = "fs-write"
+
+	// CapabilityNetwork means that the plugin accesses the network.
+	CapabilityNetwork = "network"
 )
 
 // ValueType is used as the type indicator of a KeyValue.
@@ -34,25 +66,80 @@ type Manifest struct {
 	//
 	// All of the commands defined by the plugin are subcommands of the domain.
 	// The domain is also used as a prefix for the tasks provided by the plugin.
+	//
+	// The domain may contain only lowercase ASCII letters, digits, and hyphens.
+	// It must start with a letter and it must not end with a hyphen. The words
+	// reserved by Reginald cannot be used as domains. See [ValidDomain].
 	Domain string `json:"domain"`
 
 	// Description is the description of the plugin that is shown to the user in
-	// the help message.
+	// the help message. The descriptions in the manifest may contain
+	// [text/template] actions that are executed with
+	// [Manifest.RenderDescriptions].
 	Description string `json:"description"`
 
+	// Version is the optional version of the plugin. If it is set, it must be
+	// a semantic version without the "v" prefix, for example, "1.2.3" or
+	// "1.0.0-rc.1". See [Manifest.SemVer].
+	Version string `json:"version,omitempty"`
+
+	// Homepage is the optional URL of the documentation or other homepage of
+	// the plugin. If it is set, it must be an absolute HTTP or HTTPS URL.
+	Homepage string `json:"homepage,omitempty"`
+
+	// Repository is the optional URL of the source repository of the plugin
+	// where the user can, for example, report issues. If it is set, it must be
+	// an absolute HTTP or HTTPS URL.
+	Repository string `json:"repository,omitempty"`
+
 	// Executable is the name of the executable file of the plugin in
 	// the plugin's directory.
 	Executable string `json:"executable"`
 
+	// EnvPrefix is an optional prefix that is used instead of the domain in
+	// the names of the environment variables of the plugin, after
+	// [EnvNamePrefix]. The EnvOverride of a ConfigEntry still takes precedence
+	// over it. EnvPrefix may contain only uppercase ASCII letters, digits, and
+	// underscores, and it must not start with a digit. See [Manifest.EnvName].
+	EnvPrefix string `json:"envPrefix,omitempty"`
+
 	// Config is a list of ConfigEntries that are used to define
 	// the configuration of the plugin.
 	Config []ConfigEntry `json:"config,omitempty"`
 
+	// GlobalConfig is a list of ConfigEntries that apply to every command of
+	// the plugin. Reginald registers the flags of these entries on every
+	// command, so the flags must not collide with the flags of the commands.
+	// The values are read from the environment the same way as the values of
+	// the plugin-level Config. See [Manifest.CommandConfig].
+	GlobalConfig []ConfigEntry `json:"globalConfig,omitempty"`
+
 	// Commands is a list of Commands that this plugin provides.
 	Commands []Command `json:"commands,omitempty"`
 
 	// Tasks is a list of Tasks that this plugin provides.
 	Tasks []Task `json:"tasks,omitempty"`
+
+	// Capabilities is a list of the capabilities that the plugin needs, for
+	// example, the network access. Reginald can use them for sandboxing
+	// the plugin and for showing the user what the plugin can do. The valid
+	// capabilities are [CapabilityExec], [CapabilityFSWrite], and
+	// [CapabilityNetwork].
+	Capabilities []string `json:"capabilities,omitempty"`
+
+	// DefaultLogLevel is the optional log level that Reginald should use for
+	// the plugin unless the user sets the level. It is written as the text
+	// form of [logs.Level], for example, "WARN" or "INFO+2", and any level that
+	// logs.ParseLevel accepts is valid. If it is nil, the default level is
+	// [logs.LevelInfo]. As DefaultLogLevel is a pointer, a level that is
+	// explicitly set to INFO is also written. See [Manifest.LogLevel].
+	DefaultLogLevel *logs.Level `json:"defaultLogLevel,omitempty"`
+
+	// patterns holds the *patternCache of the compiled Pattern constraints of
+	// the config entries. It is stored when the first pattern is compiled,
+	// for example, by [Manifest.Validate]. An atomic.Value lets the cache be
+	// created without a lock while the Manifest can still be copied.
+	patterns atomic.Value
 }
 
 // A Command is the program representation of a plugin command that is defined
@@ -66,7 +153,8 @@ type Command struct {
 	Name string `json:"name"`
 
 	// Usage is the one-line usage of the command that is shown to the user in
-	// the help message. Usage should not include the plugin domain.
+	// the help message. Usage should not include the plugin domain; see
+	// [Command.NormalizedUsage].
 	Usage string `json:"usage"`
 
 	// Description is the description of the command that is shown to the user
@@ -74,12 +162,45 @@ type Command struct {
 	Description string `json:"description"`
 
 	// Aliases is a list of aliases for the command that can be used instead of
-	// Name to run this command.
+	// Name to run this command. The leading and trailing white space of
+	// the aliases is ignored. The names and the aliases must be unique among
+	// the names and the aliases of all of the commands in the plugin, so an
+	// alias must not repeat the Name of its own command.
 	Aliases []string `json:"aliases,omitempty"`
 
 	// Config is a list of ConfigEntries that are used to define
 	// the configuration of the command.
 	Config []ConfigEntry `json:"config,omitempty"`
+
+	// MutexGroups is a list of groups of flag names where the flags in each
+	// group are mutually exclusive: the user may set at most one of the flags
+	// in a group. The names must be the long names of the flags defined in
+	// Config of the command.
+	MutexGroups [][]string `json:"mutexGroups,omitempty"`
+
+	// Requires maps the long names of flags to the long names of the flags
+	// that must also be set when the flag is set. For example, the entry
+	// "output-dir": ["split"] means that "--output-dir" can only be used
+	// together with "--split". The names must be the long names of the flags
+	// defined in Config of the command.
+	Requires map[string][]string `json:"requires,omitempty"`
+
+	// ReadsStdin tells that the command reads input from the standard input,
+	// for example, when the user pipes data to it. Reginald connects its
+	// standard input to the plugin process only for the commands that set
+	// ReadsStdin so that the other commands cannot block waiting for input.
+	// See [Command.NeedsStdin].
+	ReadsStdin bool `json:"readsStdin,omitempty"`
+
+	// Hidden tells that the command is not shown in the help output or in
+	// the generated documentation. The user can still run a hidden command.
+	Hidden bool `json:"hidden,omitempty"`
+
+	// Deprecated is an optional message that tells that the command is
+	// deprecated and what to use instead. Reginald shows the message when
+	// the user runs the command, and the generated documentation marks
+	// the command as deprecated.
+	Deprecated string `json:"deprecated,omitempty"`
 }
 
 // A Task is the program representation of a plugin task that is defined in
@@ -90,13 +211,34 @@ type Task struct {
 	// the task type when a task is defined in the config file.
 	Type string `json:"type"`
 
+	// Aliases is a list of alternative task types that resolve to this task.
+	// They can be used in the config file instead of Type, for example, to keep
+	// the old type working after renaming a task. The aliases must be unique
+	// among the types and the aliases of all of the tasks in the plugin.
+	Aliases []string `json:"aliases,omitempty"`
+
 	// Description is the description of the task that is shown to the user in
 	// the help message.
 	Description string `json:"description"`
 
-	// Config is a list of KeyValues that are used to define the configuration
-	// of the task.
-	Config []KeyValue `json:"config,omitempty"`
+	// Config is a list of ConfigEntries that are used to define
+	// the configuration of the task. As tasks are configured in the config
+	// file, the ConfigEntries of a task must not have a Flag.
+	//
+	// Earlier versions of the manifest defined the task config as a list of
+	// plain KeyValues. As the fields of the KeyValue are embedded in
+	// the ConfigEntry, the config in the old format is still decoded correctly.
+	Config []ConfigEntry `json:"config,omitempty"`
+
+	// Inputs is a list of the logical data keys that the task consumes. Each
+	// of the inputs must be produced by exactly one task in the plugin, and
+	// the task depends on the task that produces it. See [Manifest.TaskGraph].
+	Inputs []string `json:"inputs,omitempty"`
+
+	// Outputs is a list of the logical data keys that the task produces for
+	// the tasks that run after it. A key can be produced by only one task in
+	// the plugin.
+	Outputs []string `json:"outputs,omitempty"`
 }
 
 // A Flag is a command-line flag the is defined in the manifest for a plugin
@@ -110,18 +252,24 @@ type Flag struct {
 	//
 	// If the flag name is empty but the flag is associated with a ConfigEntry
 	// in the plugin or in a plugin command, the key of the ConfigEntry is used
-	// as the name of the flag.
+	// as the name of the flag. See [ConfigEntry.FlagName].
 	Name string `json:"name"`
 
 	// Shorthand is the short one-letter name of the flag, used in the form of
-	// "-e". The shorthand can be omitted if the flag shouldn't have one.
+	// "-e". The shorthand can be omitted if the flag shouldn't have one. It must
+	// be a single Unicode letter or digit. Non-ASCII letters, like "é", are
+	// allowed as the shorthand is counted in runes and not in bytes.
 	Shorthand string `json:"shorthand"`
 
 	// Description is the description of the flag that is shown to the user in
 	// the help message.
 	Description string `json:"description"`
 
-	// TODO: Add inverse flag for booleans.
+	// Inverse is the optional long name of the flag that sets a boolean
+	// ConfigEntry to false, for example, "no-color" for the flag "color".
+	// Inverse can only be used with a [BoolValue] and the flag must also have
+	// a positive form, either Name or the key of the ConfigEntry.
+	Inverse string `json:"inverse,omitempty"`
 }
 
 // A KeyValue is a key-value pair that is used to define a config value in the
@@ -137,11 +285,30 @@ type KeyValue struct {
 	// of the manifest, Value should contain the default value of the KeyValue.
 	// When KeyValue is used to send data from Reginald to the plugin, Value
 	// contains the current value of the KeyValue.
+	//
+	// A nil Value, decoded from null or an omitted value in JSON, means that
+	// no value is set. A zero value of the type, like false, 0, or "", is a set
+	// value. See [ConfigEntry.Default]. All of the accessors, [KeyValue.Bool],
+	// [KeyValue.Int], [KeyValue.Int64], [KeyValue.Uint], [KeyValue.Str], and
+	// [KeyValue.Object], return an error for a nil Value. When a KeyValue is
+	// encoded, a nil Value is encoded as the zero value of Type, see
+	// [ValueType.ZeroValue], so the receiver always gets a value of the declared
+	// type. The Value of a ConfigEntry is still encoded as null as there nil
+	// means that the entry has no default value.
 	Value any `json:"value"`
 
 	// Type is a string representation of the type of the value that this
 	// KeyValue holds.
 	Type ValueType `json:"type"`
+
+	// Fields is the schema of the nested values of an [ObjectValue]. Each of
+	// the KeyValues in Fields declares a field that the object can contain,
+	// and the Value of the field KeyValue is the default value of the field.
+	// Fields must be empty if Type is not ObjectValue.
+	//
+	// When Type is ObjectValue, Value holds a map[string]any that maps
+	// the keys of the fields to their values.
+	Fields []KeyValue `json:"fields,omitempty"`
 }
 
 // A ConfigEntry is a configuration entry that is defined in the manifest. It
@@ -153,6 +320,12 @@ type KeyValue struct {
 type ConfigEntry struct {
 	KeyValue
 
+	// Description is an optional description of the ConfigEntry that is shown
+	// in the generated documentation. It is also used as the description of
+	// the flag of the ConfigEntry in the help output if the Flag has no
+	// description of its own.
+	Description string `json:"description,omitempty"`
+
 	// Flag contains the information on the possible command-line flag that is
 	// associated with this ConfigEntry. Flag must be nil if the ConfigEntry has
 	// no associated flag. Otherwise, its type must match [Flag].
@@ -163,7 +336,9 @@ type ConfigEntry struct {
 	// composed using the Key in the embedded [KeyValue]. It is appended after
 	// the prefix `REGINALD_` but if EnvOverride is used to set the name of
 	// the environment variable, the name of the plugin or the name of
-	// the command is not added to variable name automatically.
+	// the command is not added to variable name automatically. EnvOverride may
+	// contain only uppercase ASCII letters, digits, and underscores, and it
+	// must not start with a digit. See [Manifest.EnvName].
 	EnvOverride string `json:"envOverride,omitempty"`
 
 	// FlagOnly tells Reginald whether this ConfigEntry should only be
@@ -171,4 +346,265 @@ type ConfigEntry struct {
 	// read the value of this ConfigEntry from the config file or from
 	// environment variables.
 	FlagOnly bool `json:"flagOnly,omitempty"`
+
+	// Inherit optionally names the Key of a plugin-level ConfigEntry that this
+	// ConfigEntry inherits. It can only be used in the config of a command.
+	// The fields set in the inheriting ConfigEntry override the ones in
+	// the inherited entry and the unset fields are taken from the inherited
+	// entry. The inheriting ConfigEntry must not change the type of the value.
+	// See [Manifest.ResolveEntry] for the exact rules.
+	Inherit string `json:"inherit,omitempty"`
+
+	// AllowedValues optionally lists the values that are allowed for this
+	// ConfigEntry. If it is empty, any value of the correct type is allowed.
+	// The values must have the type declared in the embedded [KeyValue].
+	AllowedValues []any `json:"allowedValues,omitempty"`
+
+	// CaseInsensitive tells whether string values are matched against
+	// AllowedValues ignoring case. When a value matches, it is replaced with
+	// the spelling used in AllowedValues. CaseInsensitive can only be used with
+	// a [StringValue] or a [PathValue], and AllowedValues must not contain
+	// values that differ only by case when it is set.
+	CaseInsensitive bool `json:"caseInsensitive,omitempty"`
+
+	// Min is the optional minimum value of a numeric ConfigEntry, inclusive.
+	// It can only be set for an [IntValue] or a [UintValue].
+	Min *float64 `json:"min,omitempty"`
+
+	// Max is the optional maximum value of a numeric ConfigEntry, inclusive.
+	// It can only be set for an [IntValue] or a [UintValue].
+	Max *float64 `json:"max,omitempty"`
+
+	// Pattern is an optional regular expression that the value of a string
+	// ConfigEntry must match. The syntax is the one accepted by [regexp] and,
+	// as in JSON Schema, the expression is not anchored: it must match some
+	// part of the value unless it uses "^" and "$". It can only be set for
+	// a [StringValue] or a [PathValue].
+	Pattern string `json:"pattern,omitempty"`
+
+	// Required tells that the user must set a value for this ConfigEntry. As
+	// the value is then always given by the user, a required ConfigEntry does
+	// not need a default value. If it has one anyway, the default value
+	// satisfies the requirement, so the user only has to set the required
+	// entries without a default value. See [Manifest.RequiredConfig].
+	Required bool `json:"required,omitempty"`
+
+	// RequiredMessage is an optional message that Reginald shows to the user
+	// verbatim when the value of a required ConfigEntry is missing, for
+	// example, to tell where to get an API token. See
+	// [ConfigEntry.MissingError].
+	RequiredMessage string `json:"requiredMessage,omitempty"`
+
+	// Order is an optional hint for ordering the ConfigEntries in the help
+	// output. The entries with an explicit, non-zero Order are shown first in
+	// ascending order, and the entries without an Order are shown after them
+	// in the order they are declared in. See [SortEntries].
+	Order int `json:"order,omitempty"`
+
+	// Group is an optional label of the section that this ConfigEntry is shown
+	// under in the help output, for example "Network" or "Output". The entries
+	// without a Group are shown in the default section. See [GroupEntries].
+	Group string `json:"group,omitempty"`
+
+	// Sensitive tells that the value of this ConfigEntry is a secret, like
+	// a password or an API token, that must not be shown to the user or
+	// written to the logs. See [Manifest.SensitiveKeys].
+	Sensitive bool `json:"sensitive,omitempty"`
+
+	// FileRef tells that the value of this ConfigEntry can also be read from
+	// a file whose path the user gives, as in the common pattern of "--token"
+	// and "--token-file". Reginald then accepts the path with the flag
+	// "--<flag>-file" and with the environment variable "<name>_FILE", where
+	// <flag> and <name> are the flag and the environment variable of
+	// the entry. If the user sets both the value and the file, the value
+	// takes precedence and the file is not read. FileRef can only be set for
+	// a [StringValue] or a [PathValue]. See [ConfigEntry.ResolveFromFile].
+	FileRef bool `json:"fileRef,omitempty"`
+
+	// Hidden tells that the ConfigEntry and its flag are not shown in the help
+	// output or in the generated documentation. The user can still set
+	// the value of a hidden ConfigEntry.
+	Hidden bool `json:"hidden,omitempty"`
+
+	// Deprecated is an optional message that tells that the ConfigEntry is
+	// deprecated and what to use instead. Reginald shows the message when
+	// the user sets the value, and the generated documentation marks the entry
+	// as deprecated.
+	Deprecated string `json:"deprecated,omitempty"`
+}
+
+// MarshalIndent returns the JSON encoding of the manifest in the canonical
+// format for manifest files. The fields are written in the order they are
+// declared in the types, each level of nesting is indented with two spaces,
+// the characters special to HTML are not escaped, and the output ends with
+// a newline. The empty optional fields are omitted.
+func (m *Manifest) MarshalIndent() ([]byte, error) {
+	var buf bytes.Buffer
+
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", "  ")
+
+	if err := enc.Encode(m); err != nil {
+		return nil, fmt.Errorf("failed to marshal manifest: %w", err)
+	}
+
+	return buf.Bytes(), nil
+}
+
+// CommandManifest returns a new Manifest that describes only the command with
+// the given name or alias. The returned Manifest has the metadata,
+// the EnvPrefix, the capabilities, and the GlobalConfig of m, the command
+// itself, and only the plugin-level Config entries that the command inherits.
+// It has no tasks. The command and the config entries are shallow copies that
+// share their slices with m. It returns an error if the command does not
+// exist.
+func (m *Manifest) CommandManifest(name string) (*Manifest, error) {
+	c, ok := m.LookupCommand(name)
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", errCommandNotFound, name)
+	}
+
+	var config []ConfigEntry
+
+	for _, e := range m.Config {
+		if slices.ContainsFunc(c.Config, func(ce ConfigEntry) bool { return ce.Inherit == e.Key }) {
+			config = append(config, e)
+		}
+	}
+
+	return &Manifest{
+		Name:            m.Name,
+		Domain:          m.Domain,
+		Description:     m.Description,
+		Version:         m.Version,
+		Homepage:        m.Homepage,
+		Repository:      m.Repository,
+		Executable:      m.Executable,
+		EnvPrefix:       m.EnvPrefix,
+		Config:          config,
+		GlobalConfig:    m.GlobalConfig,
+		Commands:        []Command{c},
+		Tasks:           nil,
+		Capabilities:    m.Capabilities,
+		DefaultLogLevel: m.DefaultLogLevel,
+		patterns:        atomic.Value{},
+	}, nil
+}
+
+// AddCommand appends the command to the Commands of the manifest and returns
+// the manifest for chaining. Unlike [ManifestBuilder], it does not validate
+// the manifest.
+func (m *Manifest) AddCommand(c Command) *Manifest {
+	m.Commands = append(m.Commands, c)
+
+	return m
+}
+
+// AddTask appends the task to the Tasks of the manifest and returns
+// the manifest for chaining.
+func (m *Manifest) AddTask(t Task) *Manifest {
+	m.Tasks = append(m.Tasks, t)
+
+	return m
+}
+
+// AddConfig appends the entry to the plugin-level Config of the manifest and
+// returns the manifest for chaining.
+func (m *Manifest) AddConfig(e ConfigEntry) *Manifest {
+	m.Config = append(m.Config, e)
+
+	return m
+}
+
+// HasCapability reports whether the plugin declares the given capability.
+func (m *Manifest) HasCapability(c string) bool {
+	return slices.Contains(m.Capabilities, c)
+}
+
+// NeedsStdin reports whether Reginald should connect its standard input to
+// the plugin process when it runs the command.
+func (c Command) NeedsStdin() bool {
+	return c.ReadsStdin
+}
+
+// LookupCommand returns the command with the given name. The name is matched
+// against both the Name and the Aliases of the commands, ignoring the leading
+// and trailing white space of the aliases. It returns the first matching
+// command and reports whether such a command was found.
+func (m *Manifest) LookupCommand(name string) (Command, bool) {
+	isAlias := func(a string) bool { return strings.TrimSpace(a) == name }
+
+	for _, c := range m.Commands {
+		if c.Name == name || slices.ContainsFunc(c.Aliases, isAlias) {
+			return c, true
+		}
+	}
+
+	return Command{}, false
+}
+
+// LookupTask returns the task with the given type. The type is matched against
+// both the Type and the Aliases of the tasks, so an alias resolves to
+// the task with the canonical Type. It reports whether such a task was found.
+func (m *Manifest) LookupTask(typ string) (Task, bool) {
+	for _, t := range m.Tasks {
+		if t.Type == typ || slices.Contains(t.Aliases, typ) {
+			return t, true
+		}
+	}
+
+	return Task{}, false
+}
+
+// LogLevel returns the default log level of the plugin, which is
+// the DefaultLogLevel of the manifest or [logs.LevelInfo] if DefaultLogLevel
+// is not set.
+func (m *Manifest) LogLevel() logs.Level {
+	if m.DefaultLogLevel == nil {
+		return logs.LevelInfo
+	}
+
+	return *m.DefaultLogLevel
+}
+
+// Logger returns a new [slog.Logger] that uses the given handler and adds
+// the "plugin" attribute with the domain of the plugin to every record. Users
+// can add their own attributes to the returned logger as usual.
+func (m *Manifest) Logger(handler slog.Handler) *slog.Logger {
+	return slog.New(handler).With("plugin", m.Domain)
+}
+
+// SensitiveKeys returns the sorted keys of all of the Sensitive config
+// entries of the plugin, including the entries of the commands and the tasks.
+// The inheriting entries of the commands are resolved with
+// [Manifest.ResolveEntry] first. The keys can be given to the log handlers
+// with logs.HandlerOptions.RedactKeys to redact the values from the logs.
+func (m *Manifest) SensitiveKeys() []string {
+	keys := make(map[string]bool)
+
+	add := func(entries []ConfigEntry) {
+		for _, e := range entries {
+			if resolved, err := m.ResolveEntry(e); err == nil {
+				e = resolved
+			}
+
+			if e.Sensitive {
+				keys[e.Key] = true
+			}
+		}
+	}
+
+	add(m.Config)
+	add(m.GlobalConfig)
+
+	for _, c := range m.Commands {
+		add(c.Config)
+	}
+
+	for _, t := range m.Tasks {
+		add(t.Config)
+	}
+
+	return slices.Sorted(maps.Keys(keys))
 }
@@ -0,0 +1,375 @@
+// Copyright 2025 Antti Kivi
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/reginald-project/reginald-sdk-go/api"
+	"github.com/reginald-project/reginald-sdk-go/logs"
+)
+
+func TestManifestLogger(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+
+	m := &api.Manifest{Name: "Example", Domain: "example"}
+	logger := m.Logger(slog.NewJSONHandler(&buf, nil)).With("extra", 1)
+
+	logger.Info("hello")
+
+	got := buf.String()
+	for _, want := range []string{`"plugin":"example"`, `"extra":1`, `"msg":"hello"`} {
+		if !strings.Contains(got, want) {
+			t.Errorf("got %s, want string containing %s", got, want)
+		}
+	}
+}
+
+func TestManifestAdd(t *testing.T) {
+	t.Parallel()
+
+	m := &api.Manifest{Name: "Example", Domain: "example"}
+
+	got := m.AddCommand(api.Command{Name: "list"}).
+		AddConfig(api.NewBoolEntry("verbose", false, "")).
+		AddCommand(api.Command{Name: "show"}).
+		AddTask(api.Task{Type: "link"}).
+		AddConfig(api.NewIntEntry("jobs", 4, ""))
+
+	if got != m {
+		t.Fatalf("got %p, want receiver %p", got, m)
+	}
+
+	if len(m.Commands) != 2 || len(m.Tasks) != 1 || len(m.Config) != 2 {
+		t.Errorf(
+			"got %d commands, %d tasks, and %d entries, want 2, 1, and 2",
+			len(m.Commands),
+			len(m.Tasks),
+			len(m.Config),
+		)
+	}
+
+	if m.Commands[1].Name != "show" || m.Config[1].Key != "jobs" {
+		t.Errorf("got %+v and %+v, want the adds in order", m.Commands, m.Config)
+	}
+
+	if err := m.Validate(); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestManifestLogLevel(t *testing.T) {
+	t.Parallel()
+
+	for _, test := range []struct {
+		level string
+		want  logs.Level
+	}{
+		{"", logs.LevelInfo},
+		{`"INFO"`, logs.LevelInfo},
+		{`"warn"`, logs.LevelWarn},
+		{`"DEBUG+2"`, logs.LevelDebug.Add(2)},
+		{`"TRACE"`, logs.LevelTrace},
+	} {
+		data := `{"name":"Example","domain":"example","description":"","executable":"example"`
+		if test.level != "" {
+			data += `,"defaultLogLevel":` + test.level
+		}
+
+		m, err := api.ParseManifest([]byte(data + "}"))
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", test.level, err)
+
+			continue
+		}
+
+		if got := m.LogLevel(); got != test.want {
+			t.Errorf("%s: got %v, want %v", test.level, got, test.want)
+		}
+
+		// An explicitly set level, even the default one, is written back.
+		out, err := json.Marshal(m)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if got := strings.Contains(string(out), `"defaultLogLevel"`); got != (test.level != "") {
+			t.Errorf(
+				"%s: got %s, want defaultLogLevel written: %t",
+				test.level,
+				out,
+				test.level != "",
+			)
+		}
+	}
+
+	data := `{"name":"Example","domain":"example","description":"","executable":"example",` +
+		`"defaultLogLevel":"loud"}`
+	if _, err := api.ParseManifest([]byte(data)); err == nil {
+		t.Error("got nil error for an unknown level, want error")
+	}
+}
+
+func TestCommandNeedsStdin(t *testing.T) {
+	t.Parallel()
+
+	if c := (api.Command{Name: "import", ReadsStdin: true}); !c.NeedsStdin() {
+		t.Errorf("%s: got false, want true", c.Name)
+	}
+
+	if c := (api.Command{Name: "list"}); c.NeedsStdin() {
+		t.Errorf("%s: got true, want false", c.Name)
+	}
+}
+
+func TestManifestLookupCommand(t *testing.T) {
+	t.Parallel()
+
+	m := &api.Manifest{
+		Name:   "Example",
+		Domain: "example",
+		Commands: []api.Command{
+			{Name: "list", Aliases: []string{" ls ", "l"}},
+			{Name: "show"},
+		},
+	}
+
+	for _, test := range []struct {
+		in     string
+		want   string
+		wantOK bool
+	}{
+		{"list", "list", true},
+		{"ls", "list", true},
+		{"l", "list", true},
+		{"show", "show", true},
+		{" ls ", "", false},
+		{"remove", "", false},
+	} {
+		got, ok := m.LookupCommand(test.in)
+		if got.Name != test.want || ok != test.wantOK {
+			t.Errorf(
+				"%q: got (%q, %t), want (%q, %t)",
+				test.in,
+				got.Name,
+				ok,
+				test.want,
+				test.wantOK,
+			)
+		}
+	}
+}
+
+func TestManifestLookupTask(t *testing.T) {
+	t.Parallel()
+
+	m := &api.Manifest{
+		Name:   "Example",
+		Domain: "example",
+		Tasks: []api.Task{
+			{Type: "link", Aliases: []string{"symlink", "ln"}},
+			{Type: "copy"},
+		},
+	}
+
+	for _, test := range []struct {
+		in     string
+		want   string
+		wantOK bool
+	}{
+		{"link", "link", true},
+		{"symlink", "link", true},
+		{"ln", "link", true},
+		{"copy", "copy", true},
+		{"move", "", false},
+	} {
+		got, ok := m.LookupTask(test.in)
+		if got.Type != test.want || ok != test.wantOK {
+			t.Errorf(
+				"%q: got (%q, %t), want (%q, %t)",
+				test.in,
+				got.Type,
+				ok,
+				test.want,
+				test.wantOK,
+			)
+		}
+	}
+}
+
+func TestManifestHasCapability(t *testing.T) {
+	t.Parallel()
+
+	m := &api.Manifest{
+		Name:         "Example",
+		Domain:       "example",
+		Capabilities: []string{api.CapabilityNetwork, api.CapabilityExec},
+	}
+
+	if !m.HasCapability(api.CapabilityNetwork) || !m.HasCapability(api.CapabilityExec) {
+		t.Errorf("%v: missing declared capability", m.Capabilities)
+	}
+
+	if m.HasCapability(api.CapabilityFSWrite) {
+		t.Errorf("%v: got undeclared capability %s", m.Capabilities, api.CapabilityFSWrite)
+	}
+
+	if err := m.Validate(); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	m.Capabilities = append(m.Capabilities, "root")
+
+	want := `capabilities[2]: unknown capability: "root"`
+	if err := m.Validate(); err == nil || !strings.Contains(err.Error(), want) {
+		t.Errorf("got %v, want unknown capability error", err)
+	}
+}
+
+func TestTaskUnmarshalJSON(t *testing.T) {
+	t.Parallel()
+
+	for _, test := range []struct {
+		name string
+		in   string
+		want api.Task
+	}{
+		{
+			"legacy",
+			`{"type": "link", "description": "Links.",
+				"config": [{"key": "force", "type": "bool", "value": false}]}`,
+			api.Task{
+				Type:        "link",
+				Description: "Links.",
+				Config: []api.ConfigEntry{
+					{KeyValue: api.KeyValue{Key: "force", Type: api.BoolValue, Value: false}},
+				},
+			},
+		},
+		{
+			"entries",
+			`{
+				"type": "link",
+				"description": "Links.",
+				"config": [
+					{"key": "force", "type": "bool", "value": false, "envOverride": "LINK_FORCE"}
+				]
+			}`,
+			api.Task{
+				Type:        "link",
+				Description: "Links.",
+				Config: []api.ConfigEntry{
+					{
+						KeyValue:    api.KeyValue{Key: "force", Type: api.BoolValue, Value: false},
+						EnvOverride: "LINK_FORCE",
+					},
+				},
+			},
+		},
+	} {
+		var got api.Task
+		if err := json.Unmarshal([]byte(test.in), &got); err != nil {
+			t.Fatalf("%s: %v", test.name, err)
+		}
+
+		if !reflect.DeepEqual(got, test.want) {
+			t.Errorf("%s: got %#v, want %#v", test.name, got, test.want)
+		}
+	}
+}
+
+func TestManifestSensitiveKeys(t *testing.T) {
+	t.Parallel()
+
+	m := &api.Manifest{
+		Name:   "Example",
+		Domain: "example",
+		Config: []api.ConfigEntry{
+			{KeyValue: api.KeyValue{Key: "token", Type: api.StringValue}, Sensitive: true},
+			{KeyValue: api.KeyValue{Key: "user", Type: api.StringValue}},
+		},
+		Commands: []api.Command{
+			{
+				Name: "login",
+				Config: []api.ConfigEntry{
+					{KeyValue: api.KeyValue{Key: "login-token"}, Inherit: "token"},
+					{
+						KeyValue:  api.KeyValue{Key: "password", Type: api.StringValue},
+						Sensitive: true,
+					},
+				},
+			},
+		},
+		Tasks: []api.Task{
+			{
+				Type:   "fetch",
+				Config: []api.ConfigEntry{{KeyValue: api.KeyValue{Key: "token"}, Sensitive: true}},
+			},
+		},
+	}
+
+	if got, want := m.SensitiveKeys(), []string{
+		"login-token",
+		"password",
+		"token",
+	}; !slices.Equal(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
+
+func TestManifestCommandManifest(t *testing.T) {
+	t.Parallel()
+
+	m := fullManifest()
+
+	got, err := m.CommandManifest("s")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(got.Commands) != 1 || got.Commands[0].Name != "show" {
+		t.Fatalf("got commands %+v, want only show", got.Commands)
+	}
+
+	if len(got.Config) != 1 || got.Config[0].Key != "verbose" {
+		t.Errorf("got config %+v, want only the inherited verbose", got.Config)
+	}
+
+	if got.Tasks != nil {
+		t.Errorf("got tasks %+v, want none", got.Tasks)
+	}
+
+	sameConfig := reflect.DeepEqual(got.GlobalConfig, m.GlobalConfig)
+	if got.Domain != m.Domain || got.Version != m.Version || !sameConfig {
+		t.Errorf("got %+v, want the metadata and global config of %+v", got, m)
+	}
+
+	if err := got.Validate(); err != nil {
+		t.Errorf("command manifest is not valid: %v", err)
+	}
+
+	_, err = m.CommandManifest("missing")
+	if err == nil || !strings.Contains(err.Error(), "command not found") {
+		t.Errorf("got %v, want string containing %q", err, "command not found")
+	}
+}
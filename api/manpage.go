@@ -0,0 +1,245 @@
+// Copyright 2025 Antti Kivi
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"bytes"
+	"cmp"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// RedactedDefault is shown instead of the default value of a Sensitive config
+// entry in the generated documentation. It is the same as the value used for
+// redacting the logs.
+const RedactedDefault = "***"
+
+// Errors returned by the man page generation.
+var errInvalidSection = errors.New("invalid man page section")
+
+// roffEscaper escapes the characters that are special in man page text.
+//
+//nolint:gochecknoglobals // constant replacer
+var roffEscaper = strings.NewReplacer(`\`, `\e`, "-", `\-`)
+
+// ManPage returns a man page of the plugin in the troff man format for
+// the given manual section, usually 1. The page has the NAME, SYNOPSIS,
+// DESCRIPTION, OPTIONS, COMMANDS, and ENVIRONMENT sections built from
+// the manifest and the sections without content are omitted. OPTIONS lists
+// the flags of Config and GlobalConfig, COMMANDS lists the commands with their
+// own flags, and ENVIRONMENT lists the environment variables of the entries as
+// returned by [Manifest.EnvName]. The Hidden commands and config entries are
+// omitted and the default values of the Sensitive entries are shown as
+// [RedactedDefault]. It returns an error if the section is not between 1 and 9
+// or if the config of a command cannot be resolved.
+func (m *Manifest) ManPage(section int) ([]byte, error) {
+	if section < 1 || section > 9 {
+		return nil, fmt.Errorf("%w: %d", errInvalidSection, section)
+	}
+
+	var buf, commands, env bytes.Buffer
+
+	writeManEnv(&env, m, "", m.Config)
+	writeManEnv(&env, m, "", m.GlobalConfig)
+
+	for _, c := range m.Commands {
+		if c.Hidden {
+			continue
+		}
+
+		config := make([]ConfigEntry, 0, len(c.Config))
+
+		for _, e := range c.Config {
+			resolved, err := m.ResolveEntry(e)
+			if err != nil {
+				return nil, fmt.Errorf("command %q: %w", c.Name, err)
+			}
+
+			config = append(config, resolved)
+		}
+
+		usage := c.NormalizedUsage(m.Domain)
+
+		fmt.Fprintf(&commands, ".SS %s\n", roffQuote(usage))
+
+		if len(c.Aliases) > 0 {
+			fmt.Fprintf(&commands, "Aliases: %s\n.PP\n", roffEscape(strings.Join(c.Aliases, ", ")))
+		}
+
+		commands.WriteString(roffText(c.Description))
+		writeManFlags(&commands, config)
+		writeManEnv(&env, m, c.Name, config)
+	}
+
+	fmt.Fprintf(
+		&buf,
+		".TH %s %d \"\" %s \"Reginald Plugins\"\n",
+		roffQuote(strings.ToUpper(m.Domain)),
+		section,
+		roffQuote(strings.TrimSpace(m.Name+" "+m.Version)),
+	)
+
+	buf.WriteString(".SH NAME\n")
+	buf.WriteString(roffEscape(m.Domain))
+
+	if m.Description != "" {
+		buf.WriteString(` \- ` + roffEscape(firstLine(m.Description)))
+	}
+
+	buf.WriteString("\n.SH SYNOPSIS\n")
+	buf.WriteString(".B reginald " + roffEscape(m.Domain) + "\n")
+
+	if commands.Len() > 0 {
+		buf.WriteString(`\fIcommand\fR `)
+	}
+
+	buf.WriteString(`[\fIflags\fR]` + "\n")
+
+	if m.Description != "" {
+		buf.WriteString(".SH DESCRIPTION\n")
+		buf.WriteString(roffText(m.Description))
+	}
+
+	var options bytes.Buffer
+
+	writeManFlags(&options, m.Config)
+	writeManFlags(&options, m.GlobalConfig)
+
+	for _, s := range []struct {
+		name    string
+		content *bytes.Buffer
+	}{
+		{"OPTIONS", &options},
+		{"COMMANDS", &commands},
+		{"ENVIRONMENT", &env},
+	} {
+		if s.content.Len() > 0 {
+			buf.WriteString(".SH " + s.name + "\n")
+			buf.Write(s.content.Bytes())
+		}
+	}
+
+	return buf.Bytes(), nil
+}
+
+// writeManFlags writes the flags of the visible entries to buf as tagged
+// paragraphs.
+func writeManFlags(buf *bytes.Buffer, entries []ConfigEntry) {
+	for _, e := range entries {
+		name, ok := e.FlagName()
+		if !ok || e.Hidden {
+			continue
+		}
+
+		buf.WriteString(".TP\n")
+
+		if e.Flag.Shorthand != "" {
+			buf.WriteString(`\fB\-` + roffEscape(e.Flag.Shorthand) + `\fR, `)
+		}
+
+		buf.WriteString(`\fB\-\-` + roffEscape(name) + `\fR`)
+
+		if e.Type != BoolValue {
+			buf.WriteString(` \fI` + roffEscape(string(e.Type)) + `\fR`)
+		}
+
+		if e.Flag.Inverse != "" {
+			buf.WriteString(`, \fB\-\-` + roffEscape(e.Flag.Inverse) + `\fR`)
+		}
+
+		buf.WriteString("\n")
+
+		text := cmp.Or(e.Flag.Description, e.Description)
+		if def, ok := docDefault(e); ok {
+			text = strings.TrimSpace(text + " Default: " + def + ".")
+		}
+
+		buf.WriteString(roffText(text))
+	}
+}
+
+// writeManEnv writes the environment variables of the visible entries to buf
+// as tagged paragraphs.
+func writeManEnv(buf *bytes.Buffer, m *Manifest, command string, entries []ConfigEntry) {
+	for _, e := range entries {
+		name := m.EnvName(command, e)
+		if name == "" || e.Hidden {
+			continue
+		}
+
+		buf.WriteString(".TP\n.B " + roffEscape(name) + "\n")
+
+		text := "Sets " + e.Key + "."
+		if command != "" {
+			text = "Sets " + e.Key + " of the command " + command + "."
+		}
+
+		buf.WriteString(roffText(text))
+	}
+}
+
+// docDefault returns the default value of the entry formatted for
+// the documentation and reports whether the entry has a default value.
+func docDefault(e ConfigEntry) (string, bool) {
+	kv, ok := e.Default()
+	if !ok {
+		return "", false
+	}
+
+	if e.Sensitive {
+		return RedactedDefault, true
+	}
+
+	return fmt.Sprint(kv.Value), true
+}
+
+// firstLine returns the first line of s.
+func firstLine(s string) string {
+	line, _, _ := strings.Cut(s, "\n")
+
+	return line
+}
+
+// roffEscape escapes s for use in man page text.
+func roffEscape(s string) string {
+	return roffEscaper.Replace(s)
+}
+
+// roffQuote escapes and quotes s for use as an argument of a man page macro.
+func roffQuote(s string) string {
+	return `"` + strings.ReplaceAll(roffEscape(s), `"`, `\(dq`) + `"`
+}
+
+// roffText escapes s for use as a paragraph of man page text. The lines that
+// would be read as control lines are protected and the text ends with
+// a newline. It returns the empty string for empty s.
+func roffText(s string) string {
+	if s == "" {
+		return ""
+	}
+
+	var sb strings.Builder
+
+	for line := range strings.Lines(strings.TrimSuffix(s, "\n") + "\n") {
+		if strings.HasPrefix(line, ".") || strings.HasPrefix(line, "'") {
+			sb.WriteString(`\&`)
+		}
+
+		sb.WriteString(roffEscape(line))
+	}
+
+	return sb.String()
+}
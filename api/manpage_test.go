@@ -0,0 +1,114 @@
+// Copyright 2025 Antti Kivi
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/reginald-project/reginald-sdk-go/api"
+)
+
+func docManifest() *api.Manifest {
+	return &api.Manifest{
+		Name:        "Example",
+		Domain:      "example",
+		Description: "Manage examples.",
+		Version:     "1.2.3",
+		Config: []api.ConfigEntry{
+			{
+				KeyValue: api.KeyValue{Key: "config", Value: "example.toml", Type: api.PathValue},
+				Flag:     &api.Flag{Shorthand: "c", Description: "Read the config from a file."},
+			},
+			{
+				KeyValue:  api.KeyValue{Key: "token", Value: "secret", Type: api.StringValue},
+				Sensitive: true,
+			},
+			{
+				KeyValue: api.KeyValue{Key: "debug-dump", Type: api.BoolValue},
+				Flag:     &api.Flag{},
+				Hidden:   true,
+			},
+		},
+		Commands: []api.Command{
+			{
+				Name:        "list",
+				Usage:       "list [flags]",
+				Description: "List the examples.",
+				Aliases:     []string{"ls"},
+				Config: []api.ConfigEntry{
+					{
+						KeyValue: api.KeyValue{Key: "format", Value: "text", Type: api.StringValue},
+						Flag:     &api.Flag{Shorthand: "f", Description: "Output format."},
+					},
+				},
+			},
+			{Name: "internal-debug", Description: "Debug the plugin.", Hidden: true},
+		},
+		Tasks: []api.Task{
+			{
+				Type:        "link",
+				Description: "Create links.",
+				Config: []api.ConfigEntry{
+					{KeyValue: api.KeyValue{Key: "force", Value: false, Type: api.BoolValue}},
+				},
+			},
+		},
+	}
+}
+
+func TestManifestManPage(t *testing.T) {
+	t.Parallel()
+
+	out, err := docManifest().ManPage(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got := string(out)
+
+	for _, want := range []string{
+		`.TH "EXAMPLE" 1 "" "Example 1.2.3" "Reginald Plugins"`,
+		"example \\- Manage examples.\n",
+		".SH OPTIONS\n.TP\n\\fB\\-c\\fR, \\fB\\-\\-config\\fR \\fIpath\\fR\n" +
+			"Read the config from a file. Default: example.toml.\n",
+		".SS \"list [flags]\"\nAliases: ls\n",
+		"\\fB\\-f\\fR, \\fB\\-\\-format\\fR \\fIstring\\fR\n",
+		".SH ENVIRONMENT\n",
+		".B REGINALD_EXAMPLE_TOKEN\n",
+		".B REGINALD_EXAMPLE_LIST_FORMAT\n",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("got %s, want string containing %q", got, want)
+		}
+	}
+
+	for _, hidden := range []string{"debug", "secret"} {
+		if strings.Contains(got, hidden) {
+			t.Errorf("got %s, want no %q", got, hidden)
+		}
+	}
+}
+
+func TestManifestManPageSection(t *testing.T) {
+	t.Parallel()
+
+	for _, section := range []int{0, 10} {
+		_, err := docManifest().ManPage(section)
+		if err == nil || !strings.Contains(err.Error(), "section") {
+			t.Errorf("%d: got %v, want string containing %q", section, err, "section")
+		}
+	}
+}
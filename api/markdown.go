@@ -0,0 +1,182 @@
+// Copyright 2025 Antti Kivi
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+)
+
+// markdownCellEscaper escapes the text of a Markdown table cell so that it
+// stays within the cell.
+var markdownCellEscaper = strings.NewReplacer( //nolint:gochecknoglobals // constant replacer
+	"|", `\|`,
+	"\r\n", " ",
+	"\n", " ",
+)
+
+// Markdown returns the documentation of the plugin in Markdown, for example,
+// for a section of the README of the plugin. It has a heading with the Name
+// and the Description of the plugin followed by a section for the plugin
+// config, a section for each command, and a section for each task. The config
+// is shown as a table with the key, the type, the default value,
+// the description, and the environment variable of each entry as returned by
+// [Manifest.EnvName]. The Hidden commands and config entries are omitted,
+// the Deprecated ones are marked with their deprecation message, and
+// the default values of the Sensitive entries are shown as [RedactedDefault].
+// It returns an error if the config of a command cannot be resolved.
+func (m *Manifest) Markdown() ([]byte, error) {
+	var buf bytes.Buffer
+
+	fmt.Fprintf(&buf, "# %s\n", m.Name)
+	writeMarkdownText(&buf, m.Description)
+
+	config := make([]ConfigEntry, 0, len(m.Config)+len(m.GlobalConfig))
+	config = append(config, m.Config...)
+	config = append(config, m.GlobalConfig...)
+
+	if hasVisibleEntries(config) {
+		buf.WriteString("\n## Configuration\n\n")
+		writeMarkdownConfig(&buf, m, "", config)
+	}
+
+	for _, c := range m.Commands {
+		if c.Hidden {
+			continue
+		}
+
+		fmt.Fprintf(&buf, "\n## Command `%s`\n", c.Name)
+
+		if c.Deprecated != "" {
+			fmt.Fprintf(&buf, "\n**Deprecated:** %s\n", c.Deprecated)
+		}
+
+		usage := c.NormalizedUsage(m.Domain)
+
+		fmt.Fprintf(&buf, "\n```\nreginald %s %s\n```\n", m.Domain, usage)
+
+		if len(c.Aliases) > 0 {
+			fmt.Fprintf(&buf, "\nAliases: `%s`\n", strings.Join(c.Aliases, "`, `"))
+		}
+
+		writeMarkdownText(&buf, c.Description)
+
+		entries := make([]ConfigEntry, 0, len(c.Config))
+
+		for _, e := range c.Config {
+			resolved, err := m.ResolveEntry(e)
+			if err != nil {
+				return nil, fmt.Errorf("command %q: %w", c.Name, err)
+			}
+
+			entries = append(entries, resolved)
+		}
+
+		if hasVisibleEntries(entries) {
+			buf.WriteString("\n")
+			writeMarkdownConfig(&buf, m, c.Name, entries)
+		}
+	}
+
+	for _, t := range m.Tasks {
+		fmt.Fprintf(&buf, "\n## Task `%s`\n", t.Type)
+
+		if len(t.Aliases) > 0 {
+			fmt.Fprintf(&buf, "\nAliases: `%s`\n", strings.Join(t.Aliases, "`, `"))
+		}
+
+		writeMarkdownText(&buf, t.Description)
+
+		if hasVisibleEntries(t.Config) {
+			buf.WriteString("\n")
+			writeMarkdownConfig(&buf, nil, "", t.Config)
+		}
+	}
+
+	return buf.Bytes(), nil
+}
+
+// writeMarkdownConfig writes the visible entries to buf as a Markdown table.
+// If m is nil, the entries are not read from the environment and the column
+// of the environment variables is omitted.
+func writeMarkdownConfig(buf *bytes.Buffer, m *Manifest, command string, entries []ConfigEntry) {
+	if m != nil {
+		buf.WriteString("| Key | Type | Default | Description | Environment variable |\n")
+		buf.WriteString("| --- | --- | --- | --- | --- |\n")
+	} else {
+		buf.WriteString("| Key | Type | Default | Description |\n")
+		buf.WriteString("| --- | --- | --- | --- |\n")
+	}
+
+	for _, e := range entries {
+		if e.Hidden {
+			continue
+		}
+
+		def := ""
+		if d, ok := docDefault(e); ok {
+			def = "`" + d + "`"
+		}
+
+		description := e.Description
+		if description == "" && e.Flag != nil {
+			description = e.Flag.Description
+		}
+
+		if e.Deprecated != "" {
+			description = strings.TrimSpace("**Deprecated:** " + e.Deprecated + " " + description)
+		}
+
+		fmt.Fprintf(
+			buf,
+			"| `%s` | %s | %s | %s |",
+			e.Key,
+			e.Type,
+			markdownCellEscaper.Replace(def),
+			markdownCellEscaper.Replace(description),
+		)
+
+		if m != nil {
+			if name := m.EnvName(command, e); name != "" {
+				fmt.Fprintf(buf, " `%s` |", name)
+			} else {
+				buf.WriteString("  |")
+			}
+		}
+
+		buf.WriteString("\n")
+	}
+}
+
+// writeMarkdownText writes s to buf as a paragraph if it is not empty.
+func writeMarkdownText(buf *bytes.Buffer, s string) {
+	if s == "" {
+		return
+	}
+
+	buf.WriteString("\n" + strings.TrimSuffix(s, "\n") + "\n")
+}
+
+// hasVisibleEntries reports whether any of the entries is not hidden.
+func hasVisibleEntries(entries []ConfigEntry) bool {
+	for _, e := range entries {
+		if !e.Hidden {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,69 @@
+// Copyright 2025 Antti Kivi
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/reginald-project/reginald-sdk-go/api"
+)
+
+func TestManifestMarkdown(t *testing.T) {
+	t.Parallel()
+
+	m := docManifest()
+	m.Commands[0].Config = append(m.Commands[0].Config, api.ConfigEntry{
+		KeyValue:   api.KeyValue{Key: "plain", Value: false, Type: api.BoolValue},
+		Flag:       &api.Flag{Description: "Plain output."},
+		Deprecated: "Use --format=text.",
+	}, api.ConfigEntry{
+		KeyValue:    api.KeyValue{Key: "jobs", Value: 4, Type: api.IntValue},
+		Description: "Number of jobs.",
+	})
+	m.Commands = append(m.Commands, api.Command{Name: "show", Deprecated: "Use list instead."})
+
+	out, err := m.Markdown()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got := string(out)
+
+	for _, want := range []string{
+		"# Example\n\nManage examples.\n",
+		"| `config` | path | `example.toml` | Read the config from a file. " +
+			"| `REGINALD_EXAMPLE_CONFIG` |\n",
+		"| `token` | string | `***` |  | `REGINALD_EXAMPLE_TOKEN` |\n",
+		"## Command `list`\n\n```\nreginald example list [flags]\n```\n\nAliases: `ls`\n",
+		"| `format` | string | `text` | Output format. | `REGINALD_EXAMPLE_LIST_FORMAT` |\n",
+		"| `plain` | bool | `false` | **Deprecated:** Use --format=text. Plain output. " +
+			"| `REGINALD_EXAMPLE_LIST_PLAIN` |\n",
+		"| `jobs` | int | `4` | Number of jobs. | `REGINALD_EXAMPLE_LIST_JOBS` |\n",
+		"## Command `show`\n\n**Deprecated:** Use list instead.\n",
+		"## Task `link`\n\nCreate links.\n\n| Key | Type | Default | Description |\n",
+		"| `force` | bool | `false` |  |\n",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("got %s, want string containing %q", got, want)
+		}
+	}
+
+	for _, hidden := range []string{"debug", "secret"} {
+		if strings.Contains(got, hidden) {
+			t.Errorf("got %s, want no %q", got, hidden)
+		}
+	}
+}
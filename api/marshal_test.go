@@ -0,0 +1,220 @@
+// Copyright 2025 Antti Kivi
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/reginald-project/reginald-sdk-go/api"
+	"github.com/reginald-project/reginald-sdk-go/logs"
+)
+
+//nolint:gochecknoglobals // test flag
+var update = flag.Bool("update", false, "update the golden files")
+
+// fullManifest returns a manifest with all of the fields set.
+func fullManifest() *api.Manifest {
+	minRetries, maxRetries := 0.0, 10.0
+	level := logs.LevelWarn
+
+	return &api.Manifest{
+		Name:        "Example <Plugin>",
+		Domain:      "example",
+		Description: "An example plugin.",
+		Version:     "1.2.3-rc.1+build.5",
+		Homepage:    "https://example.com/reginald?a=1&b=2",
+		Repository:  "https://github.com/example/reginald-example",
+		Executable:  "reginald-example",
+		EnvPrefix:   "EXAMPLE",
+		Config: []api.ConfigEntry{
+			{
+				KeyValue: api.KeyValue{Key: "verbose", Value: false, Type: api.BoolValue},
+				Flag: &api.Flag{
+					Name:        "verbose",
+					Shorthand:   "v",
+					Description: "Print more output.",
+				},
+				EnvOverride:     "EXAMPLE_VERBOSE",
+				FlagOnly:        true,
+				AllowedValues:   nil,
+				CaseInsensitive: false,
+				Order:           1,
+				Group:           "Output",
+			},
+			{
+				KeyValue: api.KeyValue{
+					Key:   "server",
+					Value: map[string]any{"port": uint64(8080)},
+					Type:  api.ObjectValue,
+					Fields: []api.KeyValue{
+						{Key: "port", Value: uint64(80), Type: api.UintValue},
+					},
+				},
+			},
+			{
+				KeyValue: api.KeyValue{Key: "retries", Value: 3, Type: api.IntValue},
+				Min:      &minRetries,
+				Max:      &maxRetries,
+			},
+			{
+				KeyValue:  api.KeyValue{Key: "token", Type: api.StringValue},
+				Sensitive: true,
+				FileRef:   true,
+				Hidden:    true,
+			},
+			{
+				KeyValue:   api.KeyValue{Key: "timeout", Value: 30, Type: api.IntValue},
+				Deprecated: "Use retries instead.",
+			},
+		},
+		GlobalConfig: []api.ConfigEntry{
+			{
+				KeyValue: api.KeyValue{Key: "color", Value: true, Type: api.BoolValue},
+				Flag: &api.Flag{
+					Shorthand:   "c",
+					Description: "Use colors.",
+					Inverse:     "no-color",
+				},
+			},
+		},
+		Commands: []api.Command{
+			{
+				Name:        "show",
+				Usage:       "show [flags]",
+				Description: "Show things.",
+				Aliases:     []string{"s"},
+				Config: []api.ConfigEntry{
+					{KeyValue: api.KeyValue{Key: "verbose"}, Inherit: "verbose"},
+					{
+						KeyValue: api.KeyValue{
+							Key:   "format",
+							Value: "json",
+							Type:  api.StringValue,
+						},
+						Flag:            &api.Flag{},
+						AllowedValues:   []any{"json", "yaml"},
+						CaseInsensitive: true,
+						Pattern:         "^[a-z]+$",
+						Required:        true,
+						RequiredMessage: "Choose the output format with --format.",
+					},
+					{
+						KeyValue: api.KeyValue{Key: "json", Value: false, Type: api.BoolValue},
+						Flag:     &api.Flag{},
+					},
+					{
+						KeyValue: api.KeyValue{Key: "yaml", Value: false, Type: api.BoolValue},
+						Flag:     &api.Flag{},
+					},
+				},
+				MutexGroups: [][]string{{"json", "yaml"}},
+				Requires:    map[string][]string{"yaml": {"format"}},
+				ReadsStdin:  true,
+				Hidden:      true,
+				Deprecated:  "Use list instead.",
+			},
+		},
+		Tasks: []api.Task{
+			{
+				Type:        "link",
+				Aliases:     []string{"symlink"},
+				Description: "Create links.",
+				Config: []api.ConfigEntry{
+					{KeyValue: api.KeyValue{Key: "force", Value: false, Type: api.BoolValue}},
+				},
+				Inputs:  []string{"source"},
+				Outputs: []string{"link"},
+			},
+			{
+				Type:    "fetch",
+				Outputs: []string{"source"},
+			},
+		},
+		Capabilities:    []string{api.CapabilityFSWrite},
+		DefaultLogLevel: &level,
+	}
+}
+
+func TestManifestMarshalIndent(t *testing.T) {
+	t.Parallel()
+
+	for _, test := range []struct {
+		name     string
+		manifest *api.Manifest
+	}{
+		{"manifest_full.json", fullManifest()},
+		{"manifest_empty.json", &api.Manifest{}},
+	} {
+		got, err := test.manifest.MarshalIndent()
+		if err != nil {
+			t.Fatalf("%s: %v", test.name, err)
+		}
+
+		path := filepath.Join("testdata", test.name)
+
+		if *update {
+			if err := os.WriteFile(path, got, 0o600); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		want, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if !bytes.Equal(got, want) {
+			t.Errorf("%s: got\n%s\nwant\n%s", test.name, got, want)
+		}
+
+		var decoded api.Manifest
+		if err := json.Unmarshal(got, &decoded); err != nil {
+			t.Fatalf("%s: %v", test.name, err)
+		}
+
+		again, err := decoded.MarshalIndent()
+		if err != nil {
+			t.Fatalf("%s: %v", test.name, err)
+		}
+
+		if !bytes.Equal(again, want) {
+			t.Errorf("%s: round trip: got\n%s\nwant\n%s", test.name, again, want)
+		}
+	}
+}
+
+func TestManifestMarshalIndentRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	data, err := os.ReadFile(filepath.Join("testdata", "manifest_full.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var got api.Manifest
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatal(err)
+	}
+
+	if want := fullManifest(); !reflect.DeepEqual(&got, want) {
+		t.Errorf("got %#v, want %#v", &got, want)
+	}
+}
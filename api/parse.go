@@ -0,0 +1,289 @@
+// Copyright 2025 Antti Kivi
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"slices"
+	"strings"
+)
+
+// Errors returned by the manifest parsing.
+var (
+	errManifestType = errors.New("manifest is not a JSON object")
+	errTrailingData = errors.New("unexpected data after the manifest")
+	errUnknownJSON  = errors.New("unknown field")
+)
+
+// headerSkipped are the names of the fields of Manifest that
+// [DecodeManifestHeader] skips.
+//
+//nolint:gochecknoglobals // constant list
+var headerSkipped = []string{"Config", "GlobalConfig", "Commands", "Tasks"}
+
+// ParseManifest decodes the manifest in data and validates it with
+// [Manifest.Validate]. It is meant for loading a manifest that is embedded in
+// the plugin, for example, using go:embed. Unlike [encoding/json.Unmarshal],
+// ParseManifest rejects the fields that are not defined in the manifest
+// format at any depth. The decoding errors include the byte offset of
+// the problem within data.
+func ParseManifest(data []byte) (*Manifest, error) {
+	var m Manifest
+
+	dec := json.NewDecoder(bytes.NewReader(data))
+
+	if err := dec.Decode(&m); err != nil {
+		return nil, fmt.Errorf("failed to decode manifest: %w", withOffset(err))
+	}
+
+	if _, err := dec.Token(); err == nil {
+		return nil, fmt.Errorf(
+			"failed to decode manifest: %w at offset %d",
+			errTrailingData,
+			dec.InputOffset(),
+		)
+	}
+
+	dec = json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+
+	if err := checkFields(dec, reflect.TypeFor[Manifest]()); err != nil {
+		return nil, fmt.Errorf("failed to decode manifest: %w", err)
+	}
+
+	if err := m.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid manifest: %w", err)
+	}
+
+	return &m, nil
+}
+
+// DecodeManifestHeader decodes only the header of the manifest read from r:
+// the fields of the plugin itself, like Name, Domain, and Version. The config,
+// the commands, and the tasks are skipped token by token without decoding
+// them, so it is meant for scanning many plugins, for example, when Reginald
+// discovers the installed plugins. The unknown fields are skipped too, and
+// the header is not validated as the rest of the manifest is not read. It
+// stops reading after the manifest object.
+func DecodeManifestHeader(r io.Reader) (Manifest, error) {
+	var m Manifest
+
+	dec := json.NewDecoder(r)
+
+	tok, err := dec.Token()
+	if err != nil {
+		return Manifest{}, fmt.Errorf("failed to decode manifest header: %w", withOffset(err))
+	}
+
+	if tok != json.Delim('{') {
+		return Manifest{}, fmt.Errorf("failed to decode manifest header: %w", errManifestType)
+	}
+
+	v := reflect.ValueOf(&m).Elem()
+
+	for dec.More() {
+		key, err := dec.Token()
+		if err != nil {
+			return Manifest{}, fmt.Errorf("failed to decode manifest header: %w", withOffset(err))
+		}
+
+		name, _ := key.(string)
+
+		field, ok := jsonField(v.Type(), name)
+		if !ok || slices.Contains(headerSkipped, field.Name) {
+			if err := skipNext(dec); err != nil {
+				return Manifest{}, fmt.Errorf(
+					"failed to decode manifest header: %w",
+					withOffset(err),
+				)
+			}
+
+			continue
+		}
+
+		if err := dec.Decode(v.FieldByIndex(field.Index).Addr().Interface()); err != nil {
+			return Manifest{}, fmt.Errorf(
+				"failed to decode manifest header: %q: %w",
+				name,
+				withOffset(err),
+			)
+		}
+	}
+
+	// Consume the closing delimiter.
+	if _, err := dec.Token(); err != nil {
+		return Manifest{}, fmt.Errorf("failed to decode manifest header: %w", withOffset(err))
+	}
+
+	return m, nil
+}
+
+// withOffset adds the byte offset to the JSON decoding errors that have one.
+func withOffset(err error) error {
+	var (
+		syntaxErr *json.SyntaxError
+		typeErr   *json.UnmarshalTypeError
+	)
+
+	switch {
+	case errors.As(err, &syntaxErr):
+		return fmt.Errorf("%w at offset %d", err, syntaxErr.Offset)
+	case errors.As(err, &typeErr):
+		return fmt.Errorf("%w at offset %d", err, typeErr.Offset)
+	default:
+		return err
+	}
+}
+
+// checkFields reads the next JSON value from dec and checks that every object
+// key in it is a field of the corresponding type in t. The values that are
+// decoded into interfaces are not checked.
+func checkFields(dec *json.Decoder, t reflect.Type) error {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	tok, err := dec.Token()
+	if err != nil {
+		return fmt.Errorf("%w", err)
+	}
+
+	delim, ok := tok.(json.Delim)
+	if !ok {
+		return nil
+	}
+
+	switch {
+	case delim == '[' && (t.Kind() == reflect.Slice || t.Kind() == reflect.Array):
+		for dec.More() {
+			if err := checkFields(dec, t.Elem()); err != nil {
+				return err
+			}
+		}
+	case delim == '{' && t.Kind() == reflect.Struct:
+		for dec.More() {
+			key, err := dec.Token()
+			if err != nil {
+				return fmt.Errorf("%w", err)
+			}
+
+			name, _ := key.(string)
+
+			field, ok := jsonField(t, name)
+			if !ok {
+				return fmt.Errorf("%w %q at offset %d", errUnknownJSON, name, dec.InputOffset())
+			}
+
+			if err := checkFields(dec, field.Type); err != nil {
+				return err
+			}
+		}
+	case delim == '{' && t.Kind() == reflect.Map:
+		for dec.More() {
+			if _, err := dec.Token(); err != nil {
+				return fmt.Errorf("%w", err)
+			}
+
+			if err := checkFields(dec, t.Elem()); err != nil {
+				return err
+			}
+		}
+	default:
+		return skipValue(dec)
+	}
+
+	// Consume the closing delimiter.
+	if _, err := dec.Token(); err != nil {
+		return fmt.Errorf("%w", err)
+	}
+
+	return nil
+}
+
+// jsonField returns the field of the struct type t that the JSON key name is
+// decoded into. As in encoding/json, the fields of embedded structs are
+// promoted and the names are matched ignoring case.
+func jsonField(t reflect.Type, name string) (reflect.StructField, bool) {
+	for i := range t.NumField() {
+		f := t.Field(i)
+		if !f.IsExported() {
+			continue
+		}
+
+		tag := f.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+
+		tagName, _, _ := strings.Cut(tag, ",")
+
+		if f.Anonymous && tagName == "" && f.Type.Kind() == reflect.Struct {
+			if embedded, ok := jsonField(f.Type, name); ok {
+				return embedded, true
+			}
+
+			continue
+		}
+
+		if tagName == "" {
+			tagName = f.Name
+		}
+
+		if strings.EqualFold(tagName, name) {
+			return f, true
+		}
+	}
+
+	return reflect.StructField{}, false //nolint:exhaustruct // zero value
+}
+
+// skipNext skips the next JSON value in dec.
+func skipNext(dec *json.Decoder) error {
+	tok, err := dec.Token()
+	if err != nil {
+		return fmt.Errorf("%w", err)
+	}
+
+	if tok == json.Delim('{') || tok == json.Delim('[') {
+		return skipValue(dec)
+	}
+
+	return nil
+}
+
+// skipValue skips the rest of the object or array whose opening delimiter
+// has already been read from dec.
+func skipValue(dec *json.Decoder) error {
+	for depth := 1; depth > 0; {
+		tok, err := dec.Token()
+		if err != nil {
+			return fmt.Errorf("%w", err)
+		}
+
+		switch tok {
+		case json.Delim('{'), json.Delim('['):
+			depth++
+		case json.Delim('}'), json.Delim(']'):
+			depth--
+		}
+	}
+
+	return nil
+}
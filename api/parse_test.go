@@ -0,0 +1,158 @@
+// Copyright 2025 Antti Kivi
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api_test
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/reginald-project/reginald-sdk-go/api"
+)
+
+func TestParseManifest(t *testing.T) {
+	t.Parallel()
+
+	data, err := os.ReadFile(filepath.Join("testdata", "manifest_full.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	m, err := api.ParseManifest(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The validated manifest keeps its compiled patterns, so the wanted
+	// manifest is validated, too.
+	want := fullManifest()
+	if err := want.Validate(); err != nil {
+		t.Fatal(err)
+	}
+
+	if !reflect.DeepEqual(m, want) {
+		t.Errorf("got %+v, want %+v", m, want)
+	}
+}
+
+func TestDecodeManifestHeader(t *testing.T) {
+	t.Parallel()
+
+	f, err := os.Open(filepath.Join("testdata", "manifest_full.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Cleanup(func() { f.Close() })
+
+	m, err := api.DecodeManifestHeader(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := *fullManifest()
+	want.Config, want.GlobalConfig, want.Commands, want.Tasks = nil, nil, nil, nil
+
+	if !reflect.DeepEqual(m, want) {
+		t.Errorf("got %+v, want %+v", m, want)
+	}
+
+	// The skipped arrays are not decoded, so values that do not match
+	// the manifest format are not errors there. The data after the manifest
+	// is not read.
+	data := `{"commands": [{"name": 1, "config": [{"key": []}]}], "name": "Example", ` +
+		`"tasks": [{"type": {"nested": [1, 2]}}], "domain": "example", "extra": {"a": [true]}, ` +
+		`"version": "1.2.3", "CONFIG": [null]} trailing`
+
+	m, err = api.DecodeManifestHeader(strings.NewReader(data))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if m.Name != "Example" || m.Domain != "example" || m.Version != "1.2.3" {
+		t.Errorf("got %q, %q, and %q, want the header fields", m.Name, m.Domain, m.Version)
+	}
+
+	if m.Commands != nil || m.Tasks != nil || m.Config != nil {
+		t.Errorf("got %+v, want no commands, tasks, or config", m)
+	}
+}
+
+func TestDecodeManifestHeaderError(t *testing.T) {
+	t.Parallel()
+
+	for _, test := range []struct {
+		data string
+		want string // error string should contain this
+	}{
+		{``, "failed to decode manifest header: EOF"},
+		{`[]`, "manifest is not a JSON object"},
+		{`{"name": 1}`, `"name": json: cannot unmarshal number`},
+		{`{"domain": "example", "commands": [}`, "invalid character"},
+		{`{"domain": "example"`, "unexpected end of JSON input"},
+	} {
+		_, err := api.DecodeManifestHeader(strings.NewReader(test.data))
+		if err == nil || !strings.Contains(err.Error(), test.want) {
+			t.Errorf("%s: got %v, want string containing %q", test.data, err, test.want)
+		}
+	}
+}
+
+func TestParseManifestError(t *testing.T) {
+	t.Parallel()
+
+	for _, test := range []struct {
+		data string
+		want string // error string should contain this
+	}{
+		{`{"name": "x", "domain": "example", "nmae": "y"}`, `unknown field "nmae" at offset 41`},
+		{
+			`{"domain": "example", "commands": [{"name": "run", "config": [` +
+				`{"key": "a", "type": "bool", "flag": {"shrthand": "a"}}]}]}`,
+			`unknown field "shrthand"`,
+		},
+		{
+			`{"domain": "example", "tasks": [{"type": "link", "config": [` +
+				`{"key": "a", "type": "int", "extra": 1}]}]}`,
+			`unknown field "extra"`,
+		},
+		{`{"domain": "example",}`, "at offset 22"},
+		{`{"domain": 1}`, "at offset 12"},
+		{`{"domain": "example"} {}`, "unexpected data after the manifest"},
+		{`{"domain": "Example"}`, "invalid manifest: domain: invalid domain"},
+	} {
+		_, err := api.ParseManifest([]byte(test.data))
+		if err == nil || !strings.Contains(err.Error(), test.want) {
+			t.Errorf("%s: got %v, want string containing %q", test.data, err, test.want)
+		}
+	}
+}
+
+func TestParseManifestObjectValue(t *testing.T) {
+	t.Parallel()
+
+	data := `{"domain": "example", "config": [` + objectKeyValue + `]}`
+
+	m, err := api.ParseManifest([]byte(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(m.Config) != 1 || m.Config[0].Type != api.ObjectValue {
+		t.Errorf("got config %+v, want one object entry", m.Config)
+	}
+}
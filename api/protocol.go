@@ -0,0 +1,37 @@
+// Copyright 2025 Antti Kivi
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import "slices"
+
+// ProtocolVersion is the version of the plugin protocol that this version of
+// the SDK implements. It is increased when the protocol changes in a way that
+// is not compatible with the earlier versions.
+const ProtocolVersion = 1
+
+// supportedProtocols lists the versions of the plugin protocol that this
+// version of the SDK can speak with the host, newest first. Add the older
+// versions here when ProtocolVersion is increased but the SDK still keeps
+// compatibility with them.
+//
+//   - 1: The initial version of the protocol.
+var supportedProtocols = []int{ProtocolVersion} //nolint:gochecknoglobals // constant table
+
+// CompatibleProtocol reports whether this version of the SDK can communicate
+// with a host that uses the given version of the plugin protocol. The host can
+// use it to report a precise error on a version mismatch.
+func CompatibleProtocol(hostVersion int) bool {
+	return slices.Contains(supportedProtocols, hostVersion)
+}
@@ -0,0 +1,39 @@
+// Copyright 2025 Antti Kivi
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api_test
+
+import (
+	"testing"
+
+	"github.com/reginald-project/reginald-sdk-go/api"
+)
+
+func TestCompatibleProtocol(t *testing.T) {
+	t.Parallel()
+
+	for _, test := range []struct {
+		version int
+		want    bool
+	}{
+		{api.ProtocolVersion, true},
+		{0, false},
+		{-1, false},
+		{api.ProtocolVersion + 1, false},
+	} {
+		if got := api.CompatibleProtocol(test.version); got != test.want {
+			t.Errorf("CompatibleProtocol(%d): got %t, want %t", test.version, got, test.want)
+		}
+	}
+}
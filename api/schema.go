@@ -0,0 +1,217 @@
+// Copyright 2025 Antti Kivi
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// SchemaDraft07 is the URI of the JSON Schema draft that
+// [Manifest.ConfigSchema] produces.
+const SchemaDraft07 = "http://json-schema.org/draft-07/schema#"
+
+// The keys of the objects that hold the command and the task scopes within
+// the plugin object in the schema returned by [Manifest.ConfigSchema].
+const (
+	schemaCommandsKey = "commands"
+	schemaTasksKey    = "tasks"
+)
+
+// Errors returned by the config schema generation.
+var errSchemaConflict = errors.New("config key conflicts with a scope")
+
+// schema is a JSON Schema for a single config value or a scope of config
+// values. Only the keywords needed by ConfigSchema are included.
+type schema struct {
+	Schema               string             `json:"$schema,omitempty"`
+	Title                string             `json:"title,omitempty"`
+	Description          string             `json:"description,omitempty"`
+	Type                 string             `json:"type,omitempty"`
+	Properties           map[string]*schema `json:"properties,omitempty"`
+	AdditionalProperties *bool              `json:"additionalProperties,omitempty"`
+	Required             []string           `json:"required,omitempty"`
+	Enum                 []any              `json:"enum,omitempty"`
+	Minimum              *float64           `json:"minimum,omitempty"`
+	Maximum              *float64           `json:"maximum,omitempty"`
+	Pattern              string             `json:"pattern,omitempty"`
+	Default              any                `json:"default,omitempty"`
+}
+
+// ConfigSchema returns a JSON Schema, draft 07, that describes the config
+// values of the plugin in the config file. Unlike the manifest itself, the
+// schema can be used for validating the config of the user with generic
+// tooling. The schema describes an object with the domain of the plugin as
+// its only property. The plugin object contains the keys of Config and
+// GlobalConfig, "commands" that maps the names of the commands to objects with
+// the keys of their config, and "tasks" that maps the task types to objects
+// with the keys of their config. The inheriting command entries are resolved
+// with [Manifest.ResolveEntry].
+//
+// The schema of each key has the JSON type that corresponds to the Type of
+// the entry, the AllowedValues as "enum", Min and Max as "minimum" and
+// "maximum", Pattern as "pattern", and the default value as "default".
+// The default value of a Sensitive entry is omitted so that it is not
+// exposed. The required entries without a default value are listed in
+// "required" as a default satisfies them. The enum is omitted for
+// the entries with CaseInsensitive as JSON Schema matches the values exactly.
+// The FlagOnly entries cannot be set in the config file and are not included.
+// Unknown keys are not allowed. It returns an error if a plugin-level key
+// conflicts with "commands" or "tasks" or if an entry cannot be resolved.
+func (m *Manifest) ConfigSchema() ([]byte, error) {
+	plugin := objectSchema()
+
+	for _, entries := range [][]ConfigEntry{m.Config, m.GlobalConfig} {
+		for _, e := range entries {
+			if e.Key == schemaCommandsKey || e.Key == schemaTasksKey {
+				return nil, fmt.Errorf("%w: %q", errSchemaConflict, e.Key)
+			}
+
+			plugin.addEntry(e)
+		}
+	}
+
+	if len(m.Commands) > 0 {
+		commands := objectSchema()
+
+		for _, c := range m.Commands {
+			cmd := objectSchema()
+
+			for _, e := range c.Config {
+				resolved, err := m.ResolveEntry(e)
+				if err != nil {
+					return nil, fmt.Errorf("command %q: %w", c.Name, err)
+				}
+
+				cmd.addEntry(resolved)
+			}
+
+			commands.Properties[c.Name] = cmd
+		}
+
+		plugin.Properties[schemaCommandsKey] = commands
+	}
+
+	if len(m.Tasks) > 0 {
+		tasks := objectSchema()
+
+		for _, t := range m.Tasks {
+			task := objectSchema()
+
+			for _, e := range t.Config {
+				task.addEntry(e)
+			}
+
+			tasks.Properties[t.Type] = task
+		}
+
+		plugin.Properties[schemaTasksKey] = tasks
+	}
+
+	root := objectSchema()
+	root.Schema = SchemaDraft07
+	root.Title = m.Name
+	root.Properties[m.Domain] = plugin
+
+	var buf bytes.Buffer
+
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", "  ")
+
+	if err := enc.Encode(root); err != nil {
+		return nil, fmt.Errorf("failed to encode config schema: %w", err)
+	}
+
+	return buf.Bytes(), nil
+}
+
+// objectSchema returns a new schema for an object that allows only
+// the properties that are added to it.
+func objectSchema() *schema {
+	closed := false
+
+	return &schema{ //nolint:exhaustruct // the rest are set by the caller
+		Type:                 "object",
+		Properties:           make(map[string]*schema),
+		AdditionalProperties: &closed,
+	}
+}
+
+// addEntry adds the key of the entry to the properties of the object schema s.
+// FlagOnly entries are skipped.
+func (s *schema) addEntry(e ConfigEntry) {
+	if e.FlagOnly {
+		return
+	}
+
+	v := valueSchema(e.KeyValue)
+
+	if len(e.AllowedValues) > 0 && !e.CaseInsensitive {
+		v.Enum = e.AllowedValues
+	}
+
+	if e.Min != nil && (v.Minimum == nil || *e.Min > *v.Minimum) {
+		v.Minimum = e.Min
+	}
+
+	v.Maximum = e.Max
+	v.Pattern = e.Pattern
+
+	v.Description = e.Description
+	if v.Description == "" && e.Flag != nil {
+		v.Description = e.Flag.Description
+	}
+
+	kv, hasDefault := e.Default()
+	if hasDefault && !e.Sensitive {
+		v.Default = kv.Value
+	}
+
+	s.Properties[e.Key] = v
+
+	if e.Required && !hasDefault {
+		s.Required = append(s.Required, e.Key)
+	}
+}
+
+// valueSchema returns the schema for the type of kv. The fields of an object
+// are described recursively.
+func valueSchema(kv KeyValue) *schema {
+	switch kv.Type {
+	case BoolValue:
+		return &schema{Type: "boolean"} //nolint:exhaustruct // only the type is needed
+	case IntValue:
+		return &schema{Type: "integer"} //nolint:exhaustruct // only the type is needed
+	case UintValue:
+		zero := 0.0
+
+		return &schema{Type: "integer", Minimum: &zero} //nolint:exhaustruct // the rest are unset
+	case StringValue, PathValue:
+		return &schema{Type: "string"} //nolint:exhaustruct // only the type is needed
+	case ObjectValue:
+		s := objectSchema()
+
+		for _, f := range kv.Fields {
+			s.Properties[f.Key] = valueSchema(f)
+		}
+
+		return s
+	default:
+		return &schema{} //nolint:exhaustruct // any value is allowed
+	}
+}
@@ -0,0 +1,285 @@
+// Copyright 2025 Antti Kivi
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"regexp"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/reginald-project/reginald-sdk-go/api"
+)
+
+func TestManifestConfigSchema(t *testing.T) {
+	t.Parallel()
+
+	minJobs, maxJobs := 1.0, 16.0
+	m := &api.Manifest{
+		Name:   "Example",
+		Domain: "example",
+		Config: []api.ConfigEntry{
+			{
+				KeyValue: api.KeyValue{Key: "jobs", Value: 4, Type: api.IntValue},
+				Min:      &minJobs,
+				Max:      &maxJobs,
+			},
+			{
+				KeyValue: api.KeyValue{Key: "token", Type: api.StringValue},
+				Required: true,
+				Pattern:  "^tok_",
+			},
+			{
+				KeyValue: api.KeyValue{Key: "dry-run", Value: false, Type: api.BoolValue},
+				FlagOnly: true,
+			},
+		},
+		Commands: []api.Command{
+			{
+				Name: "list",
+				Config: []api.ConfigEntry{
+					{
+						KeyValue: api.KeyValue{
+							Key:   "format",
+							Value: "text",
+							Type:  api.StringValue,
+						},
+						AllowedValues: []any{"text", "json"},
+					},
+					{KeyValue: api.KeyValue{Key: "jobs", Type: api.IntValue}, Inherit: "jobs"},
+				},
+			},
+		},
+		Tasks: []api.Task{
+			{
+				Type: "link",
+				Config: []api.ConfigEntry{
+					{KeyValue: api.KeyValue{Key: "force", Type: api.BoolValue}},
+				},
+			},
+		},
+	}
+
+	data, err := m.ConfigSchema()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var schema map[string]any
+
+	if err := json.Unmarshal(data, &schema); err != nil {
+		t.Fatalf("invalid schema: %v", err)
+	}
+
+	if schema["$schema"] != api.SchemaDraft07 {
+		t.Errorf("got $schema %v, want %q", schema["$schema"], api.SchemaDraft07)
+	}
+
+	for _, test := range []struct {
+		config string
+		want   string // error string should contain this, empty for valid config
+	}{
+		{
+			`{"example":{"jobs":8,"token":"tok_1","commands":{"list":{"format":"json","jobs":2}}}}`,
+			"",
+		},
+		{`{"example":{"token":"tok_1","tasks":{"link":{"force":true}}}}`, ""},
+		{`{"example":{"jobs":17,"token":"tok_1"}}`, "example.jobs: 17 is greater than the maximum"},
+		{`{"example":{"jobs":0,"token":"tok_1"}}`, "example.jobs: 0 is less than the minimum"},
+		{`{"example":{"jobs":1.5,"token":"tok_1"}}`, "example.jobs: want integer"},
+		{`{"example":{}}`, `example: missing required property "token"`},
+		{`{"example":{"token":"secret"}}`, "example.token: does not match pattern"},
+		{`{"example":{"token":"tok_1","dry-run":true}}`, `example: unknown property "dry-run"`},
+		{
+			`{"example":{"token":"tok_1","commands":{"list":{"format":"xml"}}}}`,
+			"example.commands.list.format: not in enum",
+		},
+		{
+			`{"example":{"token":"tok_1","tasks":{"link":{"force":"yes"}}}}`,
+			"example.tasks.link.force: want boolean",
+		},
+	} {
+		var config any
+
+		if err := json.Unmarshal([]byte(test.config), &config); err != nil {
+			t.Fatal(err)
+		}
+
+		err := validateSchema("", schema, config)
+		if test.want == "" {
+			if err != nil {
+				t.Errorf("%s: unexpected error: %v", test.config, err)
+			}
+
+			continue
+		}
+
+		if err == nil || !strings.Contains(err.Error(), test.want) {
+			t.Errorf("%s: got %v, want string containing %q", test.config, err, test.want)
+		}
+	}
+}
+
+func TestManifestConfigSchemaDefaults(t *testing.T) {
+	t.Parallel()
+
+	m := &api.Manifest{
+		Name:   "Example",
+		Domain: "example",
+		Config: []api.ConfigEntry{
+			{
+				KeyValue: api.KeyValue{Key: "host", Value: "localhost", Type: api.StringValue},
+				Required: true,
+			},
+			{
+				KeyValue:  api.KeyValue{Key: "token", Value: "tok_secret", Type: api.StringValue},
+				Sensitive: true,
+			},
+			{KeyValue: api.KeyValue{Key: "user", Type: api.StringValue}, Required: true},
+		},
+	}
+
+	data, err := m.ConfigSchema()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var schema struct {
+		Properties map[string]struct {
+			Required   []string                  `json:"required"`
+			Properties map[string]map[string]any `json:"properties"`
+		} `json:"properties"`
+	}
+
+	if err := json.Unmarshal(data, &schema); err != nil {
+		t.Fatalf("invalid schema: %v", err)
+	}
+
+	plugin := schema.Properties["example"]
+
+	if want := []string{"user"}; !slices.Equal(plugin.Required, want) {
+		t.Errorf("got required %q, want %q", plugin.Required, want)
+	}
+
+	if got, want := plugin.Properties["host"]["default"], "localhost"; got != want {
+		t.Errorf("got default %v for host, want %q", got, want)
+	}
+
+	if got, ok := plugin.Properties["token"]["default"]; ok {
+		t.Errorf("got default %v for token, want none", got)
+	}
+}
+
+func TestManifestConfigSchemaConflict(t *testing.T) {
+	t.Parallel()
+
+	m := &api.Manifest{
+		Name:   "Example",
+		Domain: "example",
+		Config: []api.ConfigEntry{{KeyValue: api.KeyValue{Key: "commands", Type: api.StringValue}}},
+	}
+
+	want := "config key conflicts with a scope"
+	if _, err := m.ConfigSchema(); err == nil || !strings.Contains(err.Error(), want) {
+		t.Errorf("got %v, want string containing %q", err, want)
+	}
+}
+
+// validateSchema validates v against the subset of JSON Schema keywords that
+// Manifest.ConfigSchema uses.
+func validateSchema(path string, schema map[string]any, v any) error {
+	if err := validateSchemaType(path, schema, v); err != nil {
+		return err
+	}
+
+	if enum, ok := schema["enum"].([]any); ok && !slices.ContainsFunc(enum, func(e any) bool {
+		return reflect.DeepEqual(e, v)
+	}) {
+		return fmt.Errorf("%s: not in enum %v: %v", path, enum, v)
+	}
+
+	if n, ok := v.(float64); ok {
+		if minimum, ok := schema["minimum"].(float64); ok && n < minimum {
+			return fmt.Errorf("%s: %v is less than the minimum %v", path, n, minimum)
+		}
+
+		if maximum, ok := schema["maximum"].(float64); ok && n > maximum {
+			return fmt.Errorf("%s: %v is greater than the maximum %v", path, n, maximum)
+		}
+	}
+
+	if pattern, ok := schema["pattern"].(string); ok {
+		if s, ok := v.(string); ok && !regexp.MustCompile(pattern).MatchString(s) {
+			return fmt.Errorf("%s: does not match pattern %q: %q", path, pattern, s)
+		}
+	}
+
+	obj, ok := v.(map[string]any)
+	if !ok {
+		return nil
+	}
+
+	required, _ := schema["required"].([]any)
+	for _, key := range required {
+		if _, ok := obj[key.(string)]; !ok {
+			return fmt.Errorf("%s: missing required property %q", path, key)
+		}
+	}
+
+	props, _ := schema["properties"].(map[string]any)
+	for key, value := range obj {
+		prop, ok := props[key].(map[string]any)
+		if !ok {
+			if schema["additionalProperties"] == false {
+				return fmt.Errorf("%s: unknown property %q", path, key)
+			}
+
+			continue
+		}
+
+		if err := validateSchema(strings.TrimPrefix(path+"."+key, "."), prop, value); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func validateSchemaType(path string, schema map[string]any, v any) error {
+	var ok bool
+
+	switch schema["type"] {
+	case "boolean":
+		_, ok = v.(bool)
+	case "integer":
+		n, isNumber := v.(float64)
+		ok = isNumber && n == float64(int64(n))
+	case "string":
+		_, ok = v.(string)
+	case "object":
+		_, ok = v.(map[string]any)
+	default:
+		ok = true
+	}
+
+	if !ok {
+		return fmt.Errorf("%s: want %v, got %v", path, schema["type"], v)
+	}
+
+	return nil
+}
@@ -0,0 +1,170 @@
+// Copyright 2025 Antti Kivi
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// Errors returned by the task utilities.
+var (
+	errDependencyCycle = errors.New("tasks depend on each other in a cycle")
+	errDuplicateOutput = errors.New("output is produced by more than one task")
+	errEmptyDataKey    = errors.New("empty data key")
+	errMissingInput    = errors.New("input is not produced by any task")
+)
+
+// ConfigValue returns the declared config of the task with the given key as
+// a KeyValue with its default value, as returned by [ConfigEntry.Default], and
+// reports whether the task declares such config. The key is matched case
+// sensitively.
+func (t Task) ConfigValue(key string) (KeyValue, bool) {
+	i := indexConfigEntry(t.Config, key)
+	if i < 0 {
+		return KeyValue{}, false
+	}
+
+	kv, _ := t.Config[i].Default()
+
+	return kv, true
+}
+
+// Defaults returns the declared config of the task that has a default value,
+// in the order of the entries, as KeyValues with the values converted as in
+// [ConfigEntry.Default]. The entries without a default value are omitted.
+func (t Task) Defaults() []KeyValue {
+	var kvs []KeyValue
+
+	for _, e := range t.Config {
+		if kv, ok := e.Default(); ok {
+			kvs = append(kvs, kv)
+		}
+	}
+
+	return kvs
+}
+
+// TaskGraph returns the data-flow dependencies between the tasks of the plugin.
+// The returned map has an entry for every task, keyed by its Type, and
+// the value is the sorted list of the types of the tasks that produce
+// the Inputs of the task. It returns an error if an input is not produced by
+// any task, if an output is produced by more than one task, or if the tasks
+// depend on each other in a cycle, including a task that consumes its own
+// output, as such tasks cannot be scheduled. The error of a cycle names
+// the tasks in it.
+func (m *Manifest) TaskGraph() (map[string][]string, error) {
+	producers := make(map[string]string)
+
+	for _, t := range m.Tasks {
+		for _, out := range t.Outputs {
+			if out == "" {
+				return nil, fmt.Errorf("task %q: %w", t.Type, errEmptyDataKey)
+			}
+
+			if p, ok := producers[out]; ok {
+				return nil, fmt.Errorf("%w: %q by %q and %q", errDuplicateOutput, out, p, t.Type)
+			}
+
+			producers[out] = t.Type
+		}
+	}
+
+	graph := make(map[string][]string, len(m.Tasks))
+
+	for _, t := range m.Tasks {
+		deps := []string{}
+
+		for _, in := range t.Inputs {
+			if in == "" {
+				return nil, fmt.Errorf("task %q: %w", t.Type, errEmptyDataKey)
+			}
+
+			p, ok := producers[in]
+			if !ok {
+				return nil, fmt.Errorf("task %q: %w: %q", t.Type, errMissingInput, in)
+			}
+
+			if !slices.Contains(deps, p) {
+				deps = append(deps, p)
+			}
+		}
+
+		slices.Sort(deps)
+
+		graph[t.Type] = deps
+	}
+
+	if cycle := findCycle(m.Tasks, graph); cycle != nil {
+		return nil, fmt.Errorf("%w: %s", errDependencyCycle, strings.Join(cycle, " -> "))
+	}
+
+	return graph, nil
+}
+
+// findCycle returns the types of the tasks in the first dependency cycle of
+// graph, starting and ending with the same task, or nil if graph has no
+// cycles. The tasks are visited in the order they are declared in.
+func findCycle(tasks []Task, graph map[string][]string) []string {
+	const (
+		unvisited = iota
+		visiting
+		visited
+	)
+
+	state := make(map[string]int, len(graph))
+
+	var (
+		path  []string
+		visit func(task string) []string
+	)
+
+	visit = func(task string) []string {
+		state[task] = visiting
+		path = append(path, task)
+
+		for _, dep := range graph[task] {
+			switch state[dep] {
+			case visiting:
+				i := slices.Index(path, dep)
+
+				return append(slices.Clone(path[i:]), dep)
+			case unvisited:
+				if cycle := visit(dep); cycle != nil {
+					return cycle
+				}
+			}
+		}
+
+		path = path[:len(path)-1]
+		state[task] = visited
+
+		return nil
+	}
+
+	for _, t := range tasks {
+		if state[t.Type] != unvisited {
+			continue
+		}
+
+		if cycle := visit(t.Type); cycle != nil {
+			return cycle
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,143 @@
+// Copyright 2025 Antti Kivi
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api_test
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/reginald-project/reginald-sdk-go/api"
+)
+
+func TestTaskConfigValue(t *testing.T) {
+	t.Parallel()
+
+	task := api.Task{
+		Type: "link",
+		Config: []api.ConfigEntry{
+			{KeyValue: api.NewBoolKeyValue("force", false)},
+			{KeyValue: api.KeyValue{Key: "mode", Value: 420.0, Type: api.IntValue}},
+			{KeyValue: api.KeyValue{Key: "target", Type: api.PathValue}},
+		},
+	}
+
+	for _, test := range []struct {
+		key  string
+		want api.KeyValue
+		ok   bool
+	}{
+		{"force", api.KeyValue{Key: "force", Value: false, Type: api.BoolValue}, true},
+		{"mode", api.KeyValue{Key: "mode", Value: 420, Type: api.IntValue}, true},
+		{"target", api.KeyValue{Key: "target", Type: api.PathValue}, true},
+		{"Force", api.KeyValue{}, false},
+		{"missing", api.KeyValue{}, false},
+	} {
+		got, ok := task.ConfigValue(test.key)
+		if ok != test.ok || !reflect.DeepEqual(got, test.want) {
+			t.Errorf("%s: got %+v (%t), want %+v (%t)", test.key, got, ok, test.want, test.ok)
+		}
+	}
+
+	want := []api.KeyValue{
+		{Key: "force", Value: false, Type: api.BoolValue},
+		{Key: "mode", Value: 420, Type: api.IntValue},
+	}
+	if got := task.Defaults(); !reflect.DeepEqual(got, want) {
+		t.Errorf("got %+v, want %+v", got, want)
+	}
+
+	if got := (api.Task{Type: "noop"}).Defaults(); got != nil {
+		t.Errorf("got %+v, want nil", got)
+	}
+}
+
+func TestManifestTaskGraph(t *testing.T) {
+	t.Parallel()
+
+	m := &api.Manifest{
+		Name:   "Example",
+		Domain: "example",
+		Tasks: []api.Task{
+			{Type: "render", Outputs: []string{"file", "hash"}},
+			{Type: "fetch", Outputs: []string{"source"}},
+			{Type: "install", Inputs: []string{"source", "file", "hash"}},
+			{Type: "clean"},
+		},
+	}
+
+	got, err := m.TaskGraph()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := map[string][]string{
+		"render":  {},
+		"fetch":   {},
+		"install": {"fetch", "render"},
+		"clean":   {},
+	}
+
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+
+	if err := m.Validate(); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestManifestTaskGraphError(t *testing.T) {
+	t.Parallel()
+
+	for _, test := range []struct {
+		tasks []api.Task
+		want  string // error string should contain this
+	}{
+		{[]api.Task{{Type: "install", Inputs: []string{"file"}}}, "not produced"},
+		{
+			[]api.Task{
+				{Type: "a", Outputs: []string{"file"}},
+				{Type: "b", Outputs: []string{"file"}},
+			},
+			"more than one task",
+		},
+		{[]api.Task{{Type: "a", Outputs: []string{""}}}, "empty data key"},
+		{
+			[]api.Task{
+				{Type: "a", Inputs: []string{"y"}, Outputs: []string{"x"}},
+				{Type: "b", Inputs: []string{"x"}, Outputs: []string{"y"}},
+			},
+			"tasks depend on each other in a cycle: a -> b -> a",
+		},
+		{
+			[]api.Task{
+				{Type: "a", Outputs: []string{"x"}},
+				{Type: "c", Inputs: []string{"z"}, Outputs: []string{"z"}},
+			},
+			"tasks depend on each other in a cycle: c -> c",
+		},
+	} {
+		m := &api.Manifest{Name: "Example", Domain: "example", Tasks: test.tasks}
+
+		if _, err := m.TaskGraph(); err == nil || !strings.Contains(err.Error(), test.want) {
+			t.Errorf("%v: got %v, want string containing %q", test.tasks, err, test.want)
+		}
+
+		if err := m.Validate(); err == nil || !strings.Contains(err.Error(), test.want) {
+			t.Errorf("%v: Validate: got %v, want string containing %q", test.tasks, err, test.want)
+		}
+	}
+}
@@ -0,0 +1,205 @@
+// Copyright 2025 Antti Kivi
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"text/template"
+)
+
+// Errors returned by the description templates.
+var errInvalidTemplate = errors.New("invalid description template")
+
+// DescriptionData is the data that the description templates are executed with
+// by [Manifest.RenderDescriptions].
+type DescriptionData struct {
+	// Data is the data given to RenderDescriptions, for example, the strings
+	// of the language of the user.
+	Data any
+
+	// Key is the key of the ConfigEntry whose description or flag description
+	// is rendered. It is empty for the other descriptions.
+	Key string
+
+	// Default is the default value of the ConfigEntry whose description or
+	// flag description is rendered as returned by [ConfigEntry.Default], or
+	// nil if the entry has no default value or the description is not
+	// the description of a config entry or its flag. The default value of
+	// a Sensitive entry is [RedactedDefault].
+	Default any
+}
+
+// RenderDescriptions returns a copy of the manifest with the descriptions of
+// the plugin, the commands, the tasks, the config entries, and their flags
+// executed as [text/template] templates. The templates are executed with
+// a [DescriptionData] that holds data, so, for example, the description of
+// a flag can be written as "The output format. Defaults to {{.Default}}." The
+// descriptions that contain no actions are kept as is. Referring to a missing
+// key of a map in data is an error. The copy shares only the values of
+// the config entries with m. It returns an error naming
+// the description that cannot be parsed or executed.
+func (m *Manifest) RenderDescriptions(data any) (*Manifest, error) {
+	result := cloneManifest(m)
+
+	err := result.walkDescriptions(func(path string, s *string, e *ConfigEntry) error {
+		d := DescriptionData{Data: data, Key: "", Default: nil}
+
+		if e != nil {
+			d.Key = e.Key
+
+			if kv, ok := e.Default(); ok {
+				d.Default = kv.Value
+			}
+
+			if d.Default != nil && e.Sensitive {
+				d.Default = RedactedDefault
+			}
+		}
+
+		rendered, err := renderDescription(*s, d)
+		if err != nil {
+			return fmt.Errorf("%s: %w", path, err)
+		}
+
+		*s = rendered
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &result, nil
+}
+
+// validateDescriptions checks that the descriptions of the manifest can be
+// parsed as templates.
+func (m *Manifest) validateDescriptions() error {
+	return m.walkDescriptions(func(path string, s *string, _ *ConfigEntry) error {
+		if !strings.Contains(*s, "{{") {
+			return nil
+		}
+
+		if _, err := parseDescription(*s); err != nil {
+			return fmt.Errorf("%s: %w", path, err)
+		}
+
+		return nil
+	})
+}
+
+// walkDescriptions calls fn with the path and a pointer to each description
+// in the manifest. For the descriptions of the config entries and their flags,
+// it also passes the entry.
+func (m *Manifest) walkDescriptions(fn func(path string, s *string, e *ConfigEntry) error) error {
+	if err := fn("description", &m.Description, nil); err != nil {
+		return err
+	}
+
+	entries := func(path string, config []ConfigEntry) error {
+		for i := range config {
+			e := &config[i]
+
+			if err := fn(
+				fmt.Sprintf("%s[%d].description", path, i),
+				&e.Description,
+				e,
+			); err != nil {
+				return err
+			}
+
+			if e.Flag == nil {
+				continue
+			}
+
+			if err := fn(
+				fmt.Sprintf("%s[%d].flag.description", path, i),
+				&e.Flag.Description,
+				e,
+			); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}
+
+	if err := entries("config", m.Config); err != nil {
+		return err
+	}
+
+	if err := entries("globalConfig", m.GlobalConfig); err != nil {
+		return err
+	}
+
+	for i := range m.Commands {
+		c := &m.Commands[i]
+
+		if err := fn(fmt.Sprintf("commands[%d].description", i), &c.Description, nil); err != nil {
+			return err
+		}
+
+		if err := entries(fmt.Sprintf("commands[%d].config", i), c.Config); err != nil {
+			return err
+		}
+	}
+
+	for i := range m.Tasks {
+		t := &m.Tasks[i]
+
+		if err := fn(fmt.Sprintf("tasks[%d].description", i), &t.Description, nil); err != nil {
+			return err
+		}
+
+		if err := entries(fmt.Sprintf("tasks[%d].config", i), t.Config); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// parseDescription parses the description s as a template.
+func parseDescription(s string) (*template.Template, error) {
+	tmpl, err := template.New("description").Option("missingkey=error").Parse(s)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", errInvalidTemplate, err)
+	}
+
+	return tmpl, nil
+}
+
+// renderDescription executes the description s as a template with the given
+// data. The descriptions without actions are returned as is.
+func renderDescription(s string, data DescriptionData) (string, error) {
+	if !strings.Contains(s, "{{") {
+		return s, nil
+	}
+
+	tmpl, err := parseDescription(s)
+	if err != nil {
+		return "", err
+	}
+
+	var sb strings.Builder
+
+	if err := tmpl.Execute(&sb, data); err != nil {
+		return "", fmt.Errorf("%w: %w", errInvalidTemplate, err)
+	}
+
+	return sb.String(), nil
+}
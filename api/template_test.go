@@ -0,0 +1,110 @@
+// Copyright 2025 Antti Kivi
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/reginald-project/reginald-sdk-go/api"
+)
+
+func TestManifestRenderDescriptions(t *testing.T) {
+	t.Parallel()
+
+	m := &api.Manifest{
+		Name:        "Example",
+		Domain:      "example",
+		Description: "{{.Data.plugin}}",
+		Commands: []api.Command{
+			{
+				Name:        "list",
+				Description: "{{.Data.list}}",
+				Config: []api.ConfigEntry{
+					{
+						KeyValue: api.KeyValue{Key: "format", Value: "text", Type: api.StringValue},
+						Flag: &api.Flag{
+							Description: "Output format ({{.Key}}), defaults to {{.Default}}.",
+						},
+					},
+					{
+						KeyValue:    api.KeyValue{Key: "jobs", Value: 4, Type: api.IntValue},
+						Description: "Jobs, defaults to {{.Default}}.",
+					},
+					{
+						KeyValue: api.KeyValue{
+							Key:   "token",
+							Value: "secret",
+							Type:  api.StringValue,
+						},
+						Flag:      &api.Flag{Description: "Token, defaults to {{.Default}}."},
+						Sensitive: true,
+					},
+				},
+			},
+		},
+		Tasks: []api.Task{{Type: "link", Description: "Plain {braces}."}},
+	}
+
+	if err := m.Validate(); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := m.RenderDescriptions(map[string]string{"plugin": "Esimerkki.", "list": "Listaa."})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, test := range []struct {
+		got, want string
+	}{
+		{got.Description, "Esimerkki."},
+		{got.Commands[0].Description, "Listaa."},
+		{got.Commands[0].Config[0].Flag.Description, "Output format (format), defaults to text."},
+		{got.Commands[0].Config[1].Description, "Jobs, defaults to 4."},
+		{got.Commands[0].Config[2].Flag.Description, "Token, defaults to ***."},
+		{got.Tasks[0].Description, "Plain {braces}."},
+	} {
+		if test.got != test.want {
+			t.Errorf("got %q, want %q", test.got, test.want)
+		}
+	}
+
+	original := m.Commands[0].Config[0].Flag.Description
+	if original != "Output format ({{.Key}}), defaults to {{.Default}}." {
+		t.Errorf("original manifest was modified: %q", original)
+	}
+
+	want := `description: invalid description template`
+	_, err = m.RenderDescriptions(map[string]string{})
+	if err == nil || !strings.Contains(err.Error(), want) {
+		t.Errorf("got %v, want string containing %q", err, want)
+	}
+}
+
+func TestManifestValidateDescriptions(t *testing.T) {
+	t.Parallel()
+
+	m := &api.Manifest{
+		Name:     "Example",
+		Domain:   "example",
+		Commands: []api.Command{{Name: "list", Description: "Defaults to {{.Default"}},
+	}
+
+	want := "commands[0].description: invalid description template"
+	if err := m.Validate(); err == nil || !strings.Contains(err.Error(), want) {
+		t.Errorf("got %v, want string containing %q", err, want)
+	}
+}
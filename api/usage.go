@@ -0,0 +1,125 @@
+// Copyright 2025 Antti Kivi
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+// UsageSummary returns the summary of the plugin that Reginald shows in its
+// top-level help. The first line has the domain and the first line of
+// the Description of the plugin. It is followed by the visible commands in
+// the order they are declared in, one per line, with the usage of the command
+// as returned by [Command.NormalizedUsage] and the first line of its
+// Description in aligned columns. The Hidden commands are omitted. The summary
+// always ends with a newline.
+func (m *Manifest) UsageSummary() string {
+	var sb strings.Builder
+
+	sb.WriteString(m.Domain)
+
+	if m.Description != "" {
+		sb.WriteString(" - ")
+		sb.WriteString(firstLine(m.Description))
+	}
+
+	sb.WriteByte('\n')
+
+	type row struct{ usage, description string }
+
+	var (
+		rows  []row
+		width int
+	)
+
+	for _, c := range m.Commands {
+		if c.Hidden {
+			continue
+		}
+
+		usage := c.NormalizedUsage(m.Domain)
+
+		rows = append(rows, row{usage: usage, description: firstLine(c.Description)})
+		width = max(width, utf8.RuneCountInString(usage))
+	}
+
+	if len(rows) == 0 {
+		return sb.String()
+	}
+
+	sb.WriteString("\nCommands:\n")
+
+	for _, r := range rows {
+		sb.WriteString("  ")
+		sb.WriteString(r.usage)
+
+		if r.description != "" {
+			sb.WriteString(strings.Repeat(" ", width-utf8.RuneCountInString(r.usage)+2))
+			sb.WriteString(r.description)
+		}
+
+		sb.WriteByte('\n')
+	}
+
+	return sb.String()
+}
+
+// NormalizedUsage returns the Usage of the command without the plugin domain.
+// The Usage should not include the domain, but if it starts with the domain,
+// optionally preceded by "reginald", as if the full command line was written,
+// those words are removed. If the Usage is empty or has nothing but
+// the domain, the Name of the command is returned. See [Manifest.Lint] for
+// reporting the Usage that includes the domain.
+func (c Command) NormalizedUsage(domain string) string {
+	usage, _ := trimUsageDomain(c.Usage, domain)
+	if usage == "" {
+		return c.Name
+	}
+
+	return usage
+}
+
+// trimUsageDomain removes the leading domain, optionally preceded by
+// "reginald", from usage and reports whether usage started with the domain.
+// The surrounding white space of usage is removed.
+func trimUsageDomain(usage, domain string) (string, bool) {
+	usage = strings.TrimSpace(usage)
+	rest := usage
+
+	if after, ok := cutWord(rest, "reginald"); ok {
+		rest = after
+	}
+
+	if domain != "" {
+		if after, ok := cutWord(rest, domain); ok {
+			return after, true
+		}
+	}
+
+	return usage, false
+}
+
+// cutWord returns s without the leading word and the white space after it and
+// reports whether s starts with the word. The word must be followed by white
+// space or the end of s.
+func cutWord(s, word string) (string, bool) {
+	after, ok := strings.CutPrefix(s, word)
+	if !ok || (after != "" && after == strings.TrimLeft(after, " \t")) {
+		return s, false
+	}
+
+	return strings.TrimLeft(after, " \t"), true
+}
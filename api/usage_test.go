@@ -0,0 +1,83 @@
+// Copyright 2025 Antti Kivi
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/reginald-project/reginald-sdk-go/api"
+)
+
+func TestManifestUsageSummary(t *testing.T) {
+	t.Parallel()
+
+	m := docManifest()
+	m.Commands = append(
+		m.Commands,
+		api.Command{Name: "show", Description: "Show an example.\nMore details."},
+	)
+
+	for _, test := range []struct {
+		name     string
+		manifest *api.Manifest
+	}{
+		{"usage_summary.txt", m},
+		{"usage_summary_empty.txt", &api.Manifest{Domain: "example"}},
+	} {
+		got := test.manifest.UsageSummary()
+		path := filepath.Join("testdata", test.name)
+
+		if *update {
+			if err := os.WriteFile(path, []byte(got), 0o600); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		want, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if got != string(want) {
+			t.Errorf("%s: got\n%s\nwant\n%s", test.name, got, want)
+		}
+	}
+}
+
+func TestCommandNormalizedUsage(t *testing.T) {
+	t.Parallel()
+
+	for _, test := range []struct {
+		usage string
+		want  string
+	}{
+		{"", "run"},
+		{"run [flags] <target>", "run [flags] <target>"},
+		{"example run [flags]", "run [flags]"},
+		{"  example\trun  ", "run"},
+		{"reginald example run [flags]", "run [flags]"},
+		{"example", "run"},
+		{"examples run", "examples run"},
+		{"reginald run", "reginald run"},
+		{"run example", "run example"},
+	} {
+		c := api.Command{Name: "run", Usage: test.usage}
+		if got := c.NormalizedUsage("example"); got != test.want {
+			t.Errorf("%q: got %q, want %q", test.usage, got, test.want)
+		}
+	}
+}
@@ -0,0 +1,593 @@
+// Copyright 2025 Antti Kivi
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Errors returned by the manifest validation.
+var (
+	errAmbiguousAllowed = errors.New("allowed values differ only by case")
+	errCaseInsensitive  = errors.New("case-insensitive matching requires a string value")
+	errConstraintType   = errors.New("constraint does not match the value type")
+	errDuplicateCommand = errors.New("duplicate command name")
+	errDuplicateTask    = errors.New("duplicate task type")
+	errEmptyCommandName = errors.New("empty command name")
+	errEmptyKey         = errors.New("empty config key")
+	errEmptyTaskType    = errors.New("empty task type")
+	errFlagCollision    = errors.New("flag collision")
+	errInheritScope     = errors.New("only command config entries can inherit")
+	errInvalidRange     = errors.New("invalid range")
+	errInvalidDomain    = errors.New("invalid domain")
+	errInvalidType      = errors.New("invalid value type")
+	errInvalidShorthand = errors.New("flag shorthand must be a single letter or digit")
+	errReservedDomain   = errors.New("domain is reserved")
+	errTaskFlag         = errors.New("task config entries cannot have flags")
+	errUnexpectedFields = errors.New("only object values can have fields")
+	errUnknownCap       = errors.New("unknown capability")
+	errUnknownFlag      = errors.New("unknown flag")
+	errUnreachable      = errors.New("config entry cannot be set")
+)
+
+// Validate checks that the manifest is well-formed and returns an error
+// describing the first problem found. The returned error is prefixed with
+// the path of the offending field within the manifest.
+func (m *Manifest) Validate() error {
+	if !validDomainSyntax(m.Domain) {
+		return fmt.Errorf("domain: %w: %q", errInvalidDomain, m.Domain)
+	}
+
+	if reservedDomain(m.Domain) {
+		return fmt.Errorf("domain: %w: %q", errReservedDomain, m.Domain)
+	}
+
+	if m.Version != "" {
+		if _, _, _, err := parseSemVer(m.Version); err != nil {
+			return fmt.Errorf("version: %w", err)
+		}
+	}
+
+	if m.Homepage != "" {
+		if err := validateURL(m.Homepage); err != nil {
+			return fmt.Errorf("homepage: %w", err)
+		}
+	}
+
+	if m.Repository != "" {
+		if err := validateURL(m.Repository); err != nil {
+			return fmt.Errorf("repository: %w", err)
+		}
+	}
+
+	if m.EnvPrefix != "" {
+		if err := validateEnvFragment(m.EnvPrefix, errInvalidEnvPrefix); err != nil {
+			return fmt.Errorf("envPrefix: %w", err)
+		}
+	}
+
+	for i, c := range m.Capabilities {
+		switch c {
+		case CapabilityExec, CapabilityFSWrite, CapabilityNetwork:
+		default:
+			return fmt.Errorf("capabilities[%d]: %w: %q", i, errUnknownCap, c)
+		}
+	}
+
+	for i, e := range m.Config {
+		if e.Inherit != "" {
+			return fmt.Errorf("config[%d]: %w: %q", i, errInheritScope, e.Key)
+		}
+
+		if err := m.validateConfigEntry(e); err != nil {
+			return fmt.Errorf("config[%d]: %w", i, err)
+		}
+	}
+
+	if err := checkEntryKeys(m.Config, fmt.Sprintf("plugin %q", m.Domain)); err != nil {
+		return fmt.Errorf("config%w", err)
+	}
+
+	for i, e := range m.GlobalConfig {
+		if e.Inherit != "" {
+			return fmt.Errorf("globalConfig[%d]: %w: %q", i, errInheritScope, e.Key)
+		}
+
+		if err := m.validateConfigEntry(e); err != nil {
+			return fmt.Errorf("globalConfig[%d]: %w", i, err)
+		}
+	}
+
+	if err := checkEntryKeys(m.GlobalConfig, fmt.Sprintf("plugin %q", m.Domain)); err != nil {
+		return fmt.Errorf("globalConfig%w", err)
+	}
+
+	if err := checkFlagCollisions(m.GlobalConfig); err != nil {
+		return fmt.Errorf("globalConfig: %w", err)
+	}
+
+	commandNames := make(map[string]bool, len(m.Commands))
+
+	for i, c := range m.Commands {
+		for j, name := range append([]string{c.Name}, c.Aliases...) {
+			name = strings.TrimSpace(name)
+			if name == "" {
+				return fmt.Errorf("commands[%d]: %w", i, errEmptyCommandName)
+			}
+
+			if commandNames[name] {
+				if j == 0 {
+					return fmt.Errorf("commands[%d].name: %w: %q", i, errDuplicateCommand, name)
+				}
+
+				return fmt.Errorf(
+					"commands[%d].aliases[%d]: %w: %q",
+					i,
+					j-1,
+					errDuplicateCommand,
+					name,
+				)
+			}
+
+			commandNames[name] = true
+		}
+
+		if err := m.validateCommand(c); err != nil {
+			return fmt.Errorf("commands[%d].%w", i, err)
+		}
+	}
+
+	if err := m.checkEnvCollisions(); err != nil {
+		return err
+	}
+
+	taskTypes := make(map[string]bool, len(m.Tasks))
+
+	for i, t := range m.Tasks {
+		for j, name := range append([]string{t.Type}, t.Aliases...) {
+			if name == "" {
+				return fmt.Errorf("tasks[%d]: %w", i, errEmptyTaskType)
+			}
+
+			if taskTypes[name] {
+				if j == 0 {
+					return fmt.Errorf("tasks[%d].type: %w: %q", i, errDuplicateTask, name)
+				}
+
+				return fmt.Errorf("tasks[%d].aliases[%d]: %w: %q", i, j-1, errDuplicateTask, name)
+			}
+
+			taskTypes[name] = true
+		}
+
+		if err := m.validateTask(t); err != nil {
+			return fmt.Errorf("tasks[%d].%w", i, err)
+		}
+	}
+
+	if _, err := m.TaskGraph(); err != nil {
+		return fmt.Errorf("tasks: %w", err)
+	}
+
+	return m.validateDescriptions()
+}
+
+// ValidDomain reports whether s is a valid plugin domain. A valid domain
+// starts with a lowercase ASCII letter, ends with a lowercase ASCII letter or
+// a digit, and contains only lowercase ASCII letters, digits, and hyphens.
+// Additionally, the domain must not be one of the words reserved by Reginald
+// for its own commands: "apply", "completion", "help", "plugin", "reginald",
+// and "version".
+func ValidDomain(s string) bool {
+	return validDomainSyntax(s) && !reservedDomain(s)
+}
+
+func (m *Manifest) validateCommand(c Command) error {
+	flags := make(map[string]bool, len(c.Config))
+	entries := make([]ConfigEntry, 0, len(c.Config))
+
+	for i, e := range c.Config {
+		resolved, err := m.ResolveEntry(e)
+		if err != nil {
+			return fmt.Errorf("config[%d]: %w", i, err)
+		}
+
+		entries = append(entries, resolved)
+	}
+
+	for i, e := range entries {
+		if err := m.validateConfigEntry(e); err != nil {
+			return fmt.Errorf("config[%d]: %w", i, err)
+		}
+
+		if name, ok := e.FlagName(); ok {
+			flags[name] = true
+		}
+	}
+
+	// The inheriting entries take their keys and types from the inherited
+	// entries, so the resolved entries are checked.
+	if err := checkEntryKeys(entries, fmt.Sprintf("command %q", c.Name)); err != nil {
+		return fmt.Errorf("config%w", err)
+	}
+
+	all, err := m.CommandConfig(c.Name)
+	if err != nil {
+		return err
+	}
+
+	if err := checkFlagCollisions(all); err != nil {
+		return fmt.Errorf("config: %w", err)
+	}
+
+	// The GlobalConfig is part of the config of every command, so the keys
+	// of the command must not repeat its keys. The index is into the merged
+	// config.
+	scope := fmt.Sprintf("command %q with global config", c.Name)
+	if err := checkEntryKeys(all, scope); err != nil {
+		return fmt.Errorf("config%w", err)
+	}
+
+	for i, group := range c.MutexGroups {
+		for _, name := range group {
+			if !flags[name] {
+				return fmt.Errorf("mutexGroups[%d]: %w: %q", i, errUnknownFlag, name)
+			}
+		}
+	}
+
+	for _, name := range slices.Sorted(maps.Keys(c.Requires)) {
+		if !flags[name] {
+			return fmt.Errorf("requires: %w: %q", errUnknownFlag, name)
+		}
+
+		for _, required := range c.Requires[name] {
+			if !flags[required] {
+				return fmt.Errorf("requires[%q]: %w: %q", name, errUnknownFlag, required)
+			}
+		}
+	}
+
+	return nil
+}
+
+// checkFlagCollisions checks that the long names and the shorthands of
+// the flags of the given entries are unique.
+func checkFlagCollisions(entries []ConfigEntry) error {
+	names := make(map[string]string, len(entries))
+	shorthands := make(map[string]string, len(entries))
+
+	for _, e := range entries {
+		name, ok := e.FlagName()
+		if !ok {
+			continue
+		}
+
+		if other, ok := names[name]; ok {
+			return fmt.Errorf("%w: --%s is used by %q and %q", errFlagCollision, name, other, e.Key)
+		}
+
+		names[name] = e.Key
+
+		if s := e.Flag.Shorthand; s != "" {
+			if other, ok := shorthands[s]; ok {
+				return fmt.Errorf("%w: -%s is used by %q and %q", errFlagCollision, s, other, e.Key)
+			}
+
+			shorthands[s] = e.Key
+		}
+	}
+
+	return nil
+}
+
+func (m *Manifest) validateConfigEntry(e ConfigEntry) error {
+	if err := validateKeyValue(e.KeyValue); err != nil {
+		return err
+	}
+
+	if err := validateAllowedValues(e); err != nil {
+		return err
+	}
+
+	if e.EnvOverride != "" {
+		if err := validateEnvFragment(e.EnvOverride, errInvalidEnvOverride); err != nil {
+			return fmt.Errorf("key %q: %w", e.Key, err)
+		}
+	}
+
+	if err := m.validateConstraints(e); err != nil {
+		return err
+	}
+
+	if err := validateFlag(e); err != nil {
+		return err
+	}
+
+	if e.FileRef && !isStringType(e.Type) {
+		return fmt.Errorf(
+			"%w: file reference requires a string value, key %q has type %s",
+			errConstraintType,
+			e.Key,
+			e.Type,
+		)
+	}
+
+	// The default value must satisfy the constraints of the entry itself. If
+	// there is no default value, there is nothing to check.
+	kv := e.KeyValue
+	if err := m.ValidateValue(e, &kv); err != nil {
+		return fmt.Errorf("default value: %w", err)
+	}
+
+	return nil
+}
+
+// validateFlag checks that the flag of e can actually be used. An entry that
+// can only be set with a flag must have a flag with a name, an inverse flag
+// needs a positive form, and the shorthand must be a single rune.
+func validateFlag(e ConfigEntry) error {
+	name, ok := e.FlagName()
+
+	if e.FlagOnly && name == "" {
+		if !ok {
+			return fmt.Errorf("%w: key %q is flag-only but has no flag", errUnreachable, e.Key)
+		}
+
+		return fmt.Errorf("%w: key %q is flag-only but its flag has no name", errUnreachable, e.Key)
+	}
+
+	if ok && e.Flag.Shorthand != "" {
+		r, size := utf8.DecodeRuneInString(e.Flag.Shorthand)
+		valid := size == len(e.Flag.Shorthand) && r != utf8.RuneError
+		if !valid || (!unicode.IsLetter(r) && !unicode.IsDigit(r)) {
+			return fmt.Errorf("%w: key %q: %q", errInvalidShorthand, e.Key, e.Flag.Shorthand)
+		}
+	}
+
+	if !ok || e.Flag.Inverse == "" {
+		return nil
+	}
+
+	if e.Type != BoolValue {
+		return fmt.Errorf(
+			"%w: inverse flag requires a bool value, key %q has type %s",
+			errConstraintType,
+			e.Key,
+			e.Type,
+		)
+	}
+
+	if name == "" {
+		return fmt.Errorf(
+			"%w: key %q has an inverse flag --%s but no positive form",
+			errUnreachable,
+			e.Key,
+			e.Flag.Inverse,
+		)
+	}
+
+	return nil
+}
+
+// validateConstraints checks that the constraints of e are valid for its type.
+func (m *Manifest) validateConstraints(e ConfigEntry) error {
+	if (e.Min != nil || e.Max != nil) && e.Type != IntValue && e.Type != UintValue {
+		return fmt.Errorf(
+			"%w: min and max require a numeric value, key %q has type %s",
+			errConstraintType,
+			e.Key,
+			e.Type,
+		)
+	}
+
+	if e.Min != nil && e.Max != nil && *e.Min > *e.Max {
+		return fmt.Errorf(
+			"%w: key %q: min %v is greater than max %v",
+			errInvalidRange,
+			e.Key,
+			*e.Min,
+			*e.Max,
+		)
+	}
+
+	if e.Pattern != "" {
+		if !isStringType(e.Type) {
+			return fmt.Errorf(
+				"%w: pattern requires a string value, key %q has type %s",
+				errConstraintType,
+				e.Key,
+				e.Type,
+			)
+		}
+
+		if _, err := m.compilePattern(e); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// validateAllowedValues checks that the allowed values of e match its type
+// and that they are not ambiguous when matched ignoring case.
+func validateAllowedValues(e ConfigEntry) error {
+	if e.CaseInsensitive && !isStringType(e.Type) {
+		return fmt.Errorf("%w: key %q has type %s", errCaseInsensitive, e.Key, e.Type)
+	}
+
+	for i, a := range e.AllowedValues {
+		if _, err := coerceValue(e.Key, e.Type, e.Fields, a); err != nil {
+			return fmt.Errorf("allowedValues[%d]: %w", i, err)
+		}
+
+		if !e.CaseInsensitive {
+			continue
+		}
+
+		s, _ := a.(string) //nolint:errcheck // type is checked above
+
+		for _, b := range e.AllowedValues[:i] {
+			t, _ := b.(string) //nolint:errcheck // type is checked above
+			if strings.EqualFold(s, t) {
+				return fmt.Errorf("%w: key %q: %q and %q", errAmbiguousAllowed, e.Key, t, s)
+			}
+		}
+	}
+
+	return nil
+}
+
+// validateTask checks the config entries of t.
+func (m *Manifest) validateTask(t Task) error {
+	if err := checkEntryKeys(t.Config, fmt.Sprintf("task %q", t.Type)); err != nil {
+		return fmt.Errorf("config%w", err)
+	}
+
+	for i, e := range t.Config {
+		if e.Inherit != "" {
+			return fmt.Errorf("config[%d]: %w: %q", i, errInheritScope, e.Key)
+		}
+
+		if e.Flag != nil || e.FlagOnly {
+			return fmt.Errorf("config[%d]: %w: %q", i, errTaskFlag, e.Key)
+		}
+
+		if err := m.validateConfigEntry(e); err != nil {
+			return fmt.Errorf("config[%d]: %w", i, err)
+		}
+	}
+
+	return nil
+}
+
+// checkEntryKeys checks that every entry in entries has a unique, non-empty
+// key and a known value type. The scope names the owner of the entries in
+// the errors, for example, `task "link"`. The returned error starts with
+// the index of the offending entry.
+func checkEntryKeys(entries []ConfigEntry, scope string) error {
+	keys := make(map[string]bool, len(entries))
+
+	for i, e := range entries {
+		if e.Key == "" {
+			return fmt.Errorf("[%d]: %w: %s", i, errEmptyKey, scope)
+		}
+
+		if keys[e.Key] {
+			return fmt.Errorf("[%d]: %w: %s, key %q", i, errDuplicateKey, scope, e.Key)
+		}
+
+		keys[e.Key] = true
+
+		if !validValueType(e.Type) {
+			return fmt.Errorf("[%d]: %w: %s, key %q: %q", i, errInvalidType, scope, e.Key, e.Type)
+		}
+	}
+
+	return nil
+}
+
+// validateKeyValue checks that the value of kv matches its Type. Integer
+// values are accepted both as int and as float64 with a whole-number value,
+// as that is how JSON numbers are decoded. Only an [ObjectValue] can have
+// Fields, and the fields must have unique, non-empty keys. The fields are
+// checked recursively.
+func validateKeyValue(kv KeyValue) error {
+	if !validValueType(kv.Type) {
+		return fmt.Errorf("%w: key %q: %q", errInvalidType, kv.Key, kv.Type)
+	}
+
+	if len(kv.Fields) > 0 && kv.Type != ObjectValue {
+		return fmt.Errorf("%w: key %q has type %q", errUnexpectedFields, kv.Key, kv.Type)
+	}
+
+	if _, err := coerceValue(kv.Key, kv.Type, kv.Fields, kv.Value); err != nil {
+		return err
+	}
+
+	keys := make(map[string]bool, len(kv.Fields))
+
+	for i, f := range kv.Fields {
+		if f.Key == "" {
+			return fmt.Errorf("%w: key %q, field %d", errEmptyKey, kv.Key, i)
+		}
+
+		if keys[f.Key] {
+			return fmt.Errorf("%w: key %q", errDuplicateKey, kv.Key+"."+f.Key)
+		}
+
+		keys[f.Key] = true
+		f.Key = kv.Key + "." + f.Key
+
+		if err := validateKeyValue(f); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// validValueType reports whether t is one of the defined value types.
+func validValueType(t ValueType) bool {
+	switch t {
+	case BoolValue, IntValue, UintValue, StringValue, PathValue, ObjectValue:
+		return true
+	default:
+		return false
+	}
+}
+
+// isStringType reports whether t holds a string value. A [PathValue] is
+// a string, so it accepts the same constraints as a [StringValue].
+func isStringType(t ValueType) bool {
+	return t == StringValue || t == PathValue
+}
+
+func validDomainSyntax(s string) bool {
+	if s == "" {
+		return false
+	}
+
+	for i := range len(s) {
+		c := s[i]
+
+		switch {
+		case c >= 'a' && c <= 'z':
+		case (c >= '0' && c <= '9') || c == '-':
+			if i == 0 {
+				return false
+			}
+		default:
+			return false
+		}
+	}
+
+	return s[len(s)-1] != '-'
+}
+
+func reservedDomain(s string) bool {
+	switch s {
+	case "apply", "completion", "help", "plugin", "reginald", "version":
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,780 @@
+// Copyright 2025 Antti Kivi
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/reginald-project/reginald-sdk-go/api"
+)
+
+func TestValidDomain(t *testing.T) {
+	t.Parallel()
+
+	for _, test := range []struct {
+		in   string
+		want bool
+	}{
+		{"example", true},
+		{"my-plugin", true},
+		{"go2", true},
+		{"a", true},
+		{"", false},
+		{"Example", false},
+		{"my plugin", false},
+		{"my:plugin", false},
+		{"my_plugin", false},
+		{"-plugin", false},
+		{"plugin-", false},
+		{"2go", false},
+		{"help", false},
+		{"version", false},
+	} {
+		if got := api.ValidDomain(test.in); got != test.want {
+			t.Errorf("%q: got %t, want %t", test.in, got, test.want)
+		}
+	}
+}
+
+func TestManifestValidateDomain(t *testing.T) {
+	t.Parallel()
+
+	for _, test := range []struct {
+		in   string
+		want string // error string should contain this, empty for no error
+	}{
+		{"example", ""},
+		{"", "invalid domain"},
+		{"ex:ample", "invalid domain"},
+		{"apply", "reserved"},
+	} {
+		m := &api.Manifest{Name: "Example", Domain: test.in}
+
+		err := m.Validate()
+		if test.want == "" {
+			if err != nil {
+				t.Errorf("%q: unexpected error: %v", test.in, err)
+			}
+
+			continue
+		}
+
+		if err == nil || !strings.Contains(err.Error(), test.want) {
+			t.Errorf("%q: got %v, want string containing %q", test.in, err, test.want)
+		}
+	}
+}
+
+func TestManifestValidateValueType(t *testing.T) {
+	t.Parallel()
+
+	//nolint:govet // don't care about this in tests
+	for _, test := range []struct {
+		kv   api.KeyValue
+		want string // error string should contain this, empty for no error
+	}{
+		{api.KeyValue{Key: "a", Type: api.BoolValue, Value: true}, ""},
+		{api.KeyValue{Key: "a", Type: api.BoolValue}, ""},
+		{api.KeyValue{Key: "a", Type: api.BoolValue, Value: "true"}, "does not match"},
+		{api.KeyValue{Key: "a", Type: api.IntValue, Value: 1}, ""},
+		{api.KeyValue{Key: "a", Type: api.IntValue, Value: float64(2)}, ""},
+		{api.KeyValue{Key: "a", Type: api.IntValue, Value: 2.5}, "does not match"},
+		{api.KeyValue{Key: "a", Type: api.IntValue, Value: "1"}, "does not match"},
+		{api.KeyValue{Key: "a", Type: api.StringValue, Value: "x"}, ""},
+		{api.KeyValue{Key: "a", Type: api.StringValue, Value: false}, "does not match"},
+		{
+			api.KeyValue{
+				Key:    "a",
+				Type:   api.ObjectValue,
+				Fields: []api.KeyValue{{Key: "b", Type: api.IntValue, Value: "x"}},
+			},
+			`key "a.b"`,
+		},
+		{
+			api.KeyValue{
+				Key:    "a",
+				Type:   api.ObjectValue,
+				Fields: []api.KeyValue{{Key: "b", Type: "float"}},
+			},
+			`invalid value type: key "a.b"`,
+		},
+		{
+			api.KeyValue{
+				Key:    "a",
+				Type:   api.ObjectValue,
+				Fields: []api.KeyValue{{Key: "", Type: api.IntValue}},
+			},
+			`empty config key: key "a", field 0`,
+		},
+		{
+			api.KeyValue{
+				Key:  "a",
+				Type: api.ObjectValue,
+				Fields: []api.KeyValue{
+					{Key: "b", Type: api.IntValue},
+					{Key: "b", Type: api.StringValue},
+				},
+			},
+			`duplicate key: key "a.b"`,
+		},
+		{
+			api.KeyValue{
+				Key:    "a",
+				Type:   api.StringValue,
+				Fields: []api.KeyValue{{Key: "b", Type: api.IntValue}},
+			},
+			`only object values can have fields: key "a"`,
+		},
+	} {
+		for _, m := range []*api.Manifest{
+			{Name: "Example", Domain: "example", Config: []api.ConfigEntry{{KeyValue: test.kv}}},
+			{
+				Name:   "Example",
+				Domain: "example",
+				Commands: []api.Command{
+					{Name: "cmd", Config: []api.ConfigEntry{{KeyValue: test.kv}}},
+				},
+			},
+			{
+				Name:   "Example",
+				Domain: "example",
+				Tasks:  []api.Task{{Type: "task", Config: []api.ConfigEntry{{KeyValue: test.kv}}}},
+			},
+		} {
+			err := m.Validate()
+			if test.want == "" {
+				if err != nil {
+					t.Errorf("%#v: unexpected error: %v", test.kv, err)
+				}
+
+				continue
+			}
+
+			if err == nil || !strings.Contains(err.Error(), test.want) {
+				t.Errorf("%#v: got %v, want string containing %q", test.kv, err, test.want)
+			}
+		}
+	}
+}
+
+func TestManifestValidatePath(t *testing.T) {
+	t.Parallel()
+
+	m := &api.Manifest{
+		Name:   "Example",
+		Domain: "example",
+		Commands: []api.Command{
+			{Name: "first"},
+			{
+				Name: "second",
+				Config: []api.ConfigEntry{
+					{KeyValue: api.KeyValue{Key: "ok", Type: api.IntValue, Value: 1}},
+					{KeyValue: api.KeyValue{Key: "bad", Type: api.IntValue, Value: "1"}},
+				},
+			},
+		},
+	}
+
+	want := "commands[1].config[1]: "
+
+	err := m.Validate()
+	if err == nil || !strings.HasPrefix(err.Error(), want) {
+		t.Errorf("got %v, want prefix %q", err, want)
+	}
+}
+
+func TestManifestValidateTaskAliases(t *testing.T) {
+	t.Parallel()
+
+	for _, test := range []struct {
+		tasks []api.Task
+		want  string // error string should contain this, empty for no error
+	}{
+		{
+			[]api.Task{
+				{Type: "link", Aliases: []string{"symlink"}},
+				{Type: "copy", Aliases: []string{"cp"}},
+			},
+			"",
+		},
+		{[]api.Task{{Type: "link"}, {Type: "link"}}, `tasks[1].type: duplicate task type: "link"`},
+		{
+			[]api.Task{{Type: "link", Aliases: []string{"copy"}}, {Type: "copy"}},
+			`tasks[1].type: duplicate`,
+		},
+		{
+			[]api.Task{{Type: "link"}, {Type: "copy", Aliases: []string{"link"}}},
+			`tasks[1].aliases[0]: duplicate`,
+		},
+		{
+			[]api.Task{{Type: "link", Aliases: []string{"ln", "ln"}}},
+			`tasks[0].aliases[1]: duplicate`,
+		},
+		{[]api.Task{{Type: "link", Aliases: []string{"link"}}}, `tasks[0].aliases[0]: duplicate`},
+		{[]api.Task{{Type: "link", Aliases: []string{""}}}, "empty task type"},
+	} {
+		m := &api.Manifest{Name: "Example", Domain: "example", Tasks: test.tasks}
+
+		err := m.Validate()
+		if test.want == "" {
+			if err != nil {
+				t.Errorf("%v: unexpected error: %v", test.tasks, err)
+			}
+
+			continue
+		}
+
+		if err == nil || !strings.Contains(err.Error(), test.want) {
+			t.Errorf("%v: got %v, want string containing %q", test.tasks, err, test.want)
+		}
+	}
+}
+
+func TestManifestValidateCommandAliases(t *testing.T) {
+	t.Parallel()
+
+	for _, test := range []struct {
+		commands []api.Command
+		want     string // error string should contain this, empty for no error
+	}{
+		{
+			[]api.Command{
+				{Name: "list", Aliases: []string{"ls"}},
+				{Name: "show", Aliases: []string{"cat"}},
+			},
+			"",
+		},
+		{
+			[]api.Command{{Name: "list", Aliases: []string{"list"}}},
+			`commands[0].aliases[0]: duplicate command name: "list"`,
+		},
+		{
+			[]api.Command{{Name: "list", Aliases: []string{" list "}}},
+			`commands[0].aliases[0]: duplicate`,
+		},
+		{
+			[]api.Command{{Name: "list", Aliases: []string{"ls", "ls "}}},
+			`commands[0].aliases[1]: duplicate`,
+		},
+		{[]api.Command{{Name: "list"}, {Name: "list"}}, `commands[1].name: duplicate`},
+		{
+			[]api.Command{
+				{Name: "list", Aliases: []string{"ls"}},
+				{Name: "show", Aliases: []string{"ls"}},
+			},
+			`commands[1].aliases[0]: duplicate`,
+		},
+		{[]api.Command{{Name: "list", Aliases: []string{" "}}}, "empty command name"},
+		{[]api.Command{{Name: ""}}, "empty command name"},
+	} {
+		m := &api.Manifest{Name: "Example", Domain: "example", Commands: test.commands}
+
+		err := m.Validate()
+		if test.want == "" {
+			if err != nil {
+				t.Errorf("%v: unexpected error: %v", test.commands, err)
+			}
+
+			continue
+		}
+
+		if err == nil || !strings.Contains(err.Error(), test.want) {
+			t.Errorf("%v: got %v, want string containing %q", test.commands, err, test.want)
+		}
+	}
+}
+
+func TestManifestValidateTaskFlags(t *testing.T) {
+	t.Parallel()
+
+	for _, e := range []api.ConfigEntry{
+		{KeyValue: api.KeyValue{Key: "a", Type: api.BoolValue}, Flag: &api.Flag{}},
+		{KeyValue: api.KeyValue{Key: "a", Type: api.BoolValue}, FlagOnly: true},
+	} {
+		m := &api.Manifest{
+			Name:   "Example",
+			Domain: "example",
+			Tasks:  []api.Task{{Type: "task", Config: []api.ConfigEntry{e}}},
+		}
+
+		if err := m.Validate(); err == nil || !strings.Contains(err.Error(), "cannot have flags") {
+			t.Errorf("%#v: got %v, want task flag error", e, err)
+		}
+	}
+}
+
+func TestManifestValidateTaskConfig(t *testing.T) {
+	t.Parallel()
+
+	entry := func(key string, typ api.ValueType) api.ConfigEntry {
+		return api.ConfigEntry{KeyValue: api.KeyValue{Key: key, Type: typ}}
+	}
+
+	for _, test := range []struct {
+		config []api.ConfigEntry
+		want   string // error string should contain this, empty for no error
+	}{
+		{[]api.ConfigEntry{entry("src", api.StringValue), entry("force", api.BoolValue)}, ""},
+		{
+			[]api.ConfigEntry{entry("src", api.StringValue), entry("", api.BoolValue)},
+			`tasks[0].config[1]: empty config key: task "link"`,
+		},
+		{
+			[]api.ConfigEntry{entry("src", api.StringValue), entry("src", api.BoolValue)},
+			`tasks[0].config[1]: duplicate key: task "link", key "src"`,
+		},
+		{
+			[]api.ConfigEntry{entry("src", "list")},
+			`tasks[0].config[0]: invalid value type: task "link", key "src": "list"`,
+		},
+		{[]api.ConfigEntry{entry("src", "")}, "invalid value type"},
+	} {
+		m := &api.Manifest{
+			Name:   "Example",
+			Domain: "example",
+			Tasks:  []api.Task{{Type: "link", Config: test.config}},
+		}
+
+		err := m.Validate()
+		if test.want == "" {
+			if err != nil {
+				t.Errorf("%v: unexpected error: %v", test.config, err)
+			}
+
+			continue
+		}
+
+		if err == nil || !strings.Contains(err.Error(), test.want) {
+			t.Errorf("%v: got %v, want string containing %q", test.config, err, test.want)
+		}
+	}
+}
+
+func TestManifestValidateConfigKeys(t *testing.T) {
+	t.Parallel()
+
+	entry := func(key string, typ api.ValueType) api.ConfigEntry {
+		return api.ConfigEntry{KeyValue: api.KeyValue{Key: key, Type: typ}}
+	}
+
+	for _, test := range []struct {
+		config []api.ConfigEntry
+		want   string // error string should contain this, empty for no error
+	}{
+		{[]api.ConfigEntry{entry("a", api.StringValue), entry("b", api.BoolValue)}, ""},
+		{
+			[]api.ConfigEntry{entry("a", api.StringValue), entry("", api.BoolValue)},
+			"[1]: empty config key",
+		},
+		{
+			[]api.ConfigEntry{entry("a", api.StringValue), entry("a", api.BoolValue)},
+			"[1]: duplicate key",
+		},
+		{[]api.ConfigEntry{entry("a", "list")}, "[0]: invalid value type"},
+		{[]api.ConfigEntry{entry("a", "")}, "invalid value type"},
+	} {
+		for _, m := range []*api.Manifest{
+			{Name: "Example", Domain: "example", Config: test.config},
+			{Name: "Example", Domain: "example", GlobalConfig: test.config},
+			{
+				Name:     "Example",
+				Domain:   "example",
+				Commands: []api.Command{{Name: "build", Config: test.config}},
+			},
+		} {
+			err := m.Validate()
+			if test.want == "" {
+				if err != nil {
+					t.Errorf("%v: unexpected error: %v", test.config, err)
+				}
+
+				continue
+			}
+
+			if err == nil || !strings.Contains(err.Error(), test.want) {
+				t.Errorf("%v: got %v, want string containing %q", test.config, err, test.want)
+			}
+		}
+	}
+
+	m := inheritManifest(api.ConfigEntry{Inherit: "verbose"}, api.ConfigEntry{Inherit: "verbose"})
+
+	err := m.Validate()
+	if want := `commands[0].config[1]: duplicate key: command "build", key "verbose"`; err == nil ||
+		!strings.Contains(err.Error(), want) {
+		t.Errorf("got %v, want string containing %q", err, want)
+	}
+
+	m = &api.Manifest{
+		Name:         "Example",
+		Domain:       "example",
+		GlobalConfig: []api.ConfigEntry{entry("target", api.StringValue)},
+		Commands: []api.Command{
+			{Name: "build", Config: []api.ConfigEntry{entry("target", api.IntValue)}},
+		},
+	}
+
+	err = m.Validate()
+	if want := `commands[0].config[1]: duplicate key: command "build" with global config, ` +
+		`key "target"`; err == nil || !strings.Contains(err.Error(), want) {
+		t.Errorf("got %v, want string containing %q", err, want)
+	}
+}
+
+func TestManifestValidateDefaultConstraints(t *testing.T) {
+	t.Parallel()
+
+	one, ten := 1.0, 10.0
+
+	for _, test := range []struct {
+		entry api.ConfigEntry
+		want  string // error string should contain this, empty for no error
+	}{
+		{
+			api.ConfigEntry{
+				KeyValue: api.KeyValue{Key: "n", Type: api.IntValue, Value: 5},
+				Min:      &one,
+				Max:      &ten,
+			},
+			"",
+		},
+		{
+			api.ConfigEntry{
+				KeyValue: api.KeyValue{Key: "n", Type: api.IntValue, Value: 0},
+				Min:      &one,
+			},
+			"less than",
+		},
+		{
+			api.ConfigEntry{
+				KeyValue: api.KeyValue{Key: "n", Type: api.UintValue, Value: 11},
+				Max:      &ten,
+			},
+			"greater than",
+		},
+		{
+			api.ConfigEntry{
+				KeyValue: api.KeyValue{Key: "n", Type: api.IntValue},
+				Min:      &ten,
+				Max:      &one,
+			},
+			"invalid range",
+		},
+		{
+			api.ConfigEntry{KeyValue: api.KeyValue{Key: "n", Type: api.StringValue}, Min: &one},
+			"numeric value",
+		},
+		{
+			api.ConfigEntry{
+				KeyValue: api.KeyValue{Key: "s", Type: api.StringValue, Value: "ab1"},
+				Pattern:  `^[a-z]+\d$`,
+			},
+			"",
+		},
+		{
+			api.ConfigEntry{
+				KeyValue: api.KeyValue{Key: "s", Type: api.StringValue, Value: "AB"},
+				Pattern:  `^[a-z]+$`,
+			},
+			"does not match pattern",
+		},
+		{
+			api.ConfigEntry{KeyValue: api.KeyValue{Key: "s", Type: api.StringValue}, Pattern: `[`},
+			"invalid regular expression",
+		},
+		{
+			api.ConfigEntry{KeyValue: api.KeyValue{Key: "b", Type: api.BoolValue}, Pattern: `x`},
+			"string value",
+		},
+		{
+			api.ConfigEntry{
+				KeyValue: api.KeyValue{Key: "p", Type: api.PathValue, Value: "/tmp/a"},
+				Pattern:  `^/`,
+			},
+			"",
+		},
+		{
+			api.ConfigEntry{
+				KeyValue: api.KeyValue{Key: "p", Type: api.PathValue, Value: "a"},
+				Pattern:  `^/`,
+			},
+			"does not match pattern",
+		},
+		{
+			api.ConfigEntry{
+				KeyValue:        api.KeyValue{Key: "p", Type: api.PathValue, Value: "A"},
+				AllowedValues:   []any{"a", "b"},
+				CaseInsensitive: true,
+			},
+			"",
+		},
+		{api.ConfigEntry{KeyValue: api.KeyValue{Key: "p", Type: api.PathValue}, FileRef: true}, ""},
+		{
+			api.ConfigEntry{KeyValue: api.KeyValue{Key: "b", Type: api.BoolValue}, FileRef: true},
+			"string value",
+		},
+		{
+			api.ConfigEntry{
+				KeyValue:      api.KeyValue{Key: "s", Type: api.StringValue, Value: "c"},
+				AllowedValues: []any{"a", "b"},
+			},
+			"not allowed",
+		},
+		{
+			api.ConfigEntry{
+				KeyValue: api.KeyValue{Key: "n", Type: api.IntValue},
+				Min:      &one,
+				Required: true,
+			},
+			"",
+		},
+		{
+			api.ConfigEntry{
+				KeyValue:      api.KeyValue{Key: "s", Type: api.StringValue},
+				Pattern:       `^[a-z]+$`,
+				AllowedValues: []any{"a"},
+				Required:      true,
+			},
+			"",
+		},
+	} {
+		m := &api.Manifest{
+			Name:   "Example",
+			Domain: "example",
+			Config: []api.ConfigEntry{test.entry},
+		}
+
+		err := m.Validate()
+		if test.want == "" {
+			if err != nil {
+				t.Errorf("%#v: unexpected error: %v", test.entry, err)
+			}
+
+			continue
+		}
+
+		if err == nil || !strings.Contains(err.Error(), test.want) {
+			t.Errorf("%#v: got %v, want string containing %q", test.entry, err, test.want)
+		}
+	}
+}
+
+func TestManifestValidateUnreachableFlags(t *testing.T) {
+	t.Parallel()
+
+	for _, test := range []struct {
+		entry api.ConfigEntry
+		want  string // error string should contain this, empty for no error
+	}{
+		{
+			api.ConfigEntry{
+				KeyValue: api.KeyValue{Key: "a", Type: api.BoolValue},
+				Flag:     &api.Flag{},
+				FlagOnly: true,
+			},
+			"",
+		},
+		{
+			api.ConfigEntry{KeyValue: api.KeyValue{Key: "a", Type: api.BoolValue}, FlagOnly: true},
+			"has no flag",
+		},
+		{
+			api.ConfigEntry{
+				KeyValue: api.KeyValue{Type: api.BoolValue},
+				Flag:     &api.Flag{},
+				FlagOnly: true,
+			},
+			"flag has no name",
+		},
+		{
+			api.ConfigEntry{
+				KeyValue: api.KeyValue{Key: "color", Type: api.BoolValue},
+				Flag:     &api.Flag{Inverse: "no-color"},
+			},
+			"",
+		},
+		{
+			api.ConfigEntry{
+				KeyValue: api.KeyValue{Type: api.BoolValue},
+				Flag:     &api.Flag{Inverse: "no-color"},
+			},
+			"no positive form",
+		},
+		{
+			api.ConfigEntry{
+				KeyValue: api.KeyValue{Key: "n", Type: api.IntValue},
+				Flag:     &api.Flag{Inverse: "no-n"},
+			},
+			"requires a bool",
+		},
+	} {
+		m := &api.Manifest{
+			Name:   "Example",
+			Domain: "example",
+			Config: []api.ConfigEntry{test.entry},
+		}
+
+		err := m.Validate()
+		if test.want == "" {
+			if err != nil {
+				t.Errorf("%#v: unexpected error: %v", test.entry, err)
+			}
+
+			continue
+		}
+
+		if err == nil || !strings.Contains(err.Error(), test.want) {
+			t.Errorf("%#v: got %v, want string containing %q", test.entry, err, test.want)
+		}
+	}
+}
+
+func TestManifestValidateAllowedDefault(t *testing.T) {
+	t.Parallel()
+
+	allowed := []any{"debug", "info", "error"}
+
+	for _, test := range []struct {
+		entry api.ConfigEntry
+		want  string // error string should contain this, empty for no error
+	}{
+		{
+			api.ConfigEntry{
+				KeyValue:      api.KeyValue{Key: "level", Value: "info", Type: api.StringValue},
+				AllowedValues: allowed,
+			},
+			"",
+		},
+		{
+			api.ConfigEntry{
+				KeyValue:      api.KeyValue{Key: "level", Value: "warn", Type: api.StringValue},
+				AllowedValues: allowed,
+			},
+			"default value: value is not allowed: " +
+				"key \"level\": warn, want one of [debug info error]",
+		},
+		{
+			api.ConfigEntry{
+				KeyValue:      api.KeyValue{Key: "level", Type: api.StringValue},
+				AllowedValues: allowed,
+				Required:      true,
+			},
+			"",
+		},
+	} {
+		m := &api.Manifest{
+			Name:   "Example",
+			Domain: "example",
+			Config: []api.ConfigEntry{test.entry},
+		}
+
+		err := m.Validate()
+		if test.want == "" {
+			if err != nil {
+				t.Errorf("%#v: unexpected error: %v", test.entry, err)
+			}
+
+			continue
+		}
+
+		if err == nil || !strings.Contains(err.Error(), test.want) {
+			t.Errorf("%#v: got %v, want string containing %q", test.entry, err, test.want)
+		}
+	}
+}
+
+func TestManifestValidateShorthand(t *testing.T) {
+	t.Parallel()
+
+	for _, test := range []struct {
+		shorthand string
+		want      string // error string should contain this, empty for no error
+	}{
+		{"", ""},
+		{"v", ""},
+		{"é", ""},
+		{"ab", `flag shorthand must be a single letter or digit: key "a": "ab"`},
+		{"-", "flag shorthand must be"},
+		{"\xff", "flag shorthand must be"},
+	} {
+		m := &api.Manifest{
+			Name:   "Example",
+			Domain: "example",
+			Config: []api.ConfigEntry{
+				{
+					KeyValue: api.KeyValue{Key: "a", Type: api.BoolValue},
+					Flag:     &api.Flag{Shorthand: test.shorthand},
+				},
+			},
+		}
+
+		err := m.Validate()
+		if test.want == "" {
+			if err != nil {
+				t.Errorf("%q: unexpected error: %v", test.shorthand, err)
+			}
+
+			continue
+		}
+
+		if err == nil || !strings.Contains(err.Error(), test.want) {
+			t.Errorf("%q: got %v, want string containing %q", test.shorthand, err, test.want)
+		}
+	}
+}
+
+func TestManifestValidateMetadata(t *testing.T) {
+	t.Parallel()
+
+	for _, test := range []struct {
+		m    api.Manifest
+		want string // error string should contain this, empty for no error
+	}{
+		{
+			api.Manifest{
+				Version:    "1.2.3",
+				Homepage:   "https://example.com",
+				Repository: "http://example.com/repo",
+			},
+			"",
+		},
+		{api.Manifest{Version: "1.0.0-alpha.1+001"}, ""},
+		{api.Manifest{Version: "v1.2.3"}, "version: invalid semantic version"},
+		{api.Manifest{Version: "1.2"}, "version: invalid semantic version"},
+		{api.Manifest{Version: "01.2.3"}, "version: invalid semantic version"},
+		{api.Manifest{Version: "1.2.3-01"}, "invalid pre-release"},
+		{api.Manifest{Version: "1.2.3+"}, "invalid build metadata"},
+		{api.Manifest{Homepage: "example.com"}, "homepage: invalid URL"},
+		{api.Manifest{Homepage: "ftp://example.com"}, "homepage: invalid URL"},
+		{api.Manifest{Repository: "https://exa mple.com"}, "repository: invalid URL"},
+	} {
+		m := test.m
+		m.Name, m.Domain = "Example", "example"
+
+		err := m.Validate()
+		if test.want == "" {
+			if err != nil {
+				t.Errorf("%+v: unexpected error: %v", test.m, err)
+			}
+
+			continue
+		}
+
+		if err == nil || !strings.Contains(err.Error(), test.want) {
+			t.Errorf("%+v: got %v, want string containing %q", test.m, err, test.want)
+		}
+	}
+}
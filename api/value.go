@@ -0,0 +1,765 @@
+// Copyright 2025 Antti Kivi
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"math"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Errors returned by the value handling of KeyValues.
+var (
+	errDuplicateKey = errors.New("duplicate key")
+	errIntOverflow  = errors.New("integer value overflows int")
+	errInt64        = errors.New("integer value overflows int64")
+	errIntPrecision = errors.New("integer value cannot be represented exactly")
+	errNegativeUint = errors.New("unsigned integer value is negative")
+	errUintRange    = errors.New("unsigned integer value overflows uint64")
+	errNotObject    = errors.New("value is not an object")
+	errUnknownField = errors.New("unknown object field")
+	errUnset        = errors.New("value is not set")
+	errUnsupported  = errors.New("unsupported value type")
+	errWrongType    = errors.New("wrong value type")
+)
+
+// A ValueMismatchError is returned when a value does not match the declared
+// Type of its KeyValue, for example, when a string is given as the value of
+// an [IntValue]. It can be extracted from the returned errors with
+// [errors.As].
+type ValueMismatchError struct {
+	// Key is the key of the KeyValue. The key of a field of an [ObjectValue]
+	// is the key of the object and the key of the field joined with a dot,
+	// for example, "owner.uid".
+	Key string
+
+	// Expected is the declared Type of the value.
+	Expected ValueType
+
+	// GotType is the Go type of the value as formatted with %T, for example,
+	// "string" or "float64".
+	GotType string
+}
+
+// Error returns the key and the expected and the actual type of the value.
+func (e *ValueMismatchError) Error() string {
+	return fmt.Sprintf(
+		"value does not match type: key %q: want %s, got %s",
+		e.Key,
+		e.Expected,
+		e.GotType,
+	)
+}
+
+// DecodeKeyValues decodes the JSON array of KeyValues in data. Each value is
+// coerced to the Go type implied by its Type as in [KeyValue.UnmarshalJSON].
+// The elements are decoded independently and the returned error lists every
+// element that could not be decoded, prefixed with its index. The KeyValues
+// are returned only if all of them were decoded.
+func DecodeKeyValues(data []byte) ([]KeyValue, error) {
+	var raw []json.RawMessage
+
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("failed to decode KeyValues: %w", err)
+	}
+
+	kvs := make([]KeyValue, len(raw))
+	errs := make([]error, 0, len(raw))
+
+	for i, r := range raw {
+		if err := json.Unmarshal(r, &kvs[i]); err != nil {
+			errs = append(errs, fmt.Errorf("[%d]: %w", i, err))
+		}
+	}
+
+	if len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+
+	return kvs, nil
+}
+
+// KeyValuesToMap returns a map of the given KeyValues keyed by their keys. It
+// returns an error if the same key occurs more than once in kvs as that
+// indicates malformed input.
+func KeyValuesToMap(kvs []KeyValue) (map[string]KeyValue, error) {
+	m := make(map[string]KeyValue, len(kvs))
+
+	for _, kv := range kvs {
+		if _, ok := m[kv.Key]; ok {
+			return nil, fmt.Errorf("%w: %q", errDuplicateKey, kv.Key)
+		}
+
+		m[kv.Key] = kv
+	}
+
+	return m, nil
+}
+
+// MapToKeyValues returns the KeyValues in m as a slice sorted by their keys so
+// that the order of the result is deterministic.
+func MapToKeyValues(m map[string]KeyValue) []KeyValue {
+	kvs := make([]KeyValue, 0, len(m))
+
+	for _, k := range slices.Sorted(maps.Keys(m)) {
+		kvs = append(kvs, m[k])
+	}
+
+	return kvs
+}
+
+// NewKeyValue returns a KeyValue with the given key and value and the Type
+// inferred from the Go type of v: bool is a [BoolValue], the signed integer
+// types are an [IntValue], the unsigned integer types are a [UintValue], and
+// string is a [StringValue]. The integer values are stored as int and uint64.
+// It returns an error if the type of v is not supported or if a signed integer
+// does not fit in int on the current platform.
+func NewKeyValue(key string, v any) (KeyValue, error) {
+	kv := KeyValue{Key: key, Value: v, Type: "", Fields: nil}
+
+	switch x := v.(type) {
+	case bool:
+		kv.Type = BoolValue
+	case int, int8, int16, int32, int64:
+		n, err := coerceInt(key, reflect.ValueOf(x).Int())
+		if err != nil {
+			return KeyValue{}, err
+		}
+
+		kv.Type, kv.Value = IntValue, n
+	case uint, uint8, uint16, uint32, uint64:
+		kv.Type, kv.Value = UintValue, reflect.ValueOf(x).Uint()
+	case string:
+		kv.Type = StringValue
+	default:
+		return KeyValue{}, fmt.Errorf("%w: key %q: %T", errUnsupported, key, v)
+	}
+
+	return kv, nil
+}
+
+// Equal reports whether kv and other have the same Key, Type, and Value.
+// The values are compared according to the declared Type and not according
+// to their dynamic Go types, so, for example, an [IntValue] with the value 5
+// of type int equals an IntValue with the value 5 of type float64 as decoded
+// from JSON. The nested values of an [ObjectValue] are compared in the same
+// way using the fields declared in each KeyValue. The Fields themselves are
+// not compared. Use [ConfigEntry.Equal] to compare whole ConfigEntries.
+func (kv KeyValue) Equal(other KeyValue) bool {
+	if kv.Key != other.Key || kv.Type != other.Type {
+		return false
+	}
+
+	return reflect.DeepEqual(kv.normalizedValue(), other.normalizedValue())
+}
+
+// Equal reports whether e and other are the same ConfigEntry. The embedded
+// KeyValues are compared with [KeyValue.Equal], and the AllowedValues are
+// compared in the same type-aware way. The rest of the fields, including
+// the Flag and the Min and Max values, must be deeply equal. It shadows
+// KeyValue.Equal so that the fields of the ConfigEntry are not ignored.
+func (e ConfigEntry) Equal(other ConfigEntry) bool {
+	if !e.KeyValue.Equal(other.KeyValue) {
+		return false
+	}
+
+	if !slices.EqualFunc(e.AllowedValues, other.AllowedValues, func(a, b any) bool {
+		return reflect.DeepEqual(e.normalize(a), other.normalize(b))
+	}) {
+		return false
+	}
+
+	var zero KeyValue
+
+	a, b := e, other
+	a.KeyValue, b.KeyValue = zero, zero
+	a.AllowedValues, b.AllowedValues = nil, nil
+
+	return reflect.DeepEqual(a, b)
+}
+
+// ZeroValue returns the zero value of the Go type that corresponds to t: false
+// for a [BoolValue], int 0 for an [IntValue], uint64 0 for a [UintValue],
+// the empty string for a [StringValue] and a [PathValue], and an empty
+// map[string]any for an [ObjectValue]. It returns nil for an unknown type.
+// The zero value is a set value; a nil Value means that no value is set. It is
+// meant for the callers that want to treat an unset value as the zero value,
+// for example, after the accessors of KeyValue have reported that the value is
+// not set.
+func (t ValueType) ZeroValue() any {
+	switch t {
+	case BoolValue:
+		return false
+	case IntValue:
+		return 0
+	case UintValue:
+		return uint64(0)
+	case StringValue, PathValue:
+		return ""
+	case ObjectValue:
+		return map[string]any{}
+	default:
+		return nil
+	}
+}
+
+// Bool returns the value of a [BoolValue]. It returns an error if kv is not
+// a BoolValue, if the value is not set, or if the value is not a bool.
+func (kv KeyValue) Bool() (bool, error) {
+	if kv.Type != BoolValue {
+		return false, fmt.Errorf(
+			"%w: key %q has type %s, not %s",
+			errWrongType,
+			kv.Key,
+			kv.Type,
+			BoolValue,
+		)
+	}
+
+	if kv.Value == nil {
+		return false, fmt.Errorf("%w: key %q", errUnset, kv.Key)
+	}
+
+	b, ok := kv.Value.(bool)
+	if !ok {
+		return false, newValueMismatchError(kv.Key, kv.Type, kv.Value)
+	}
+
+	return b, nil
+}
+
+// Str returns the value of a [StringValue] or a [PathValue]. It returns an
+// error if kv is neither a StringValue nor a PathValue, if the value is not
+// set, or if the value is not a string.
+func (kv KeyValue) Str() (string, error) {
+	if kv.Type != StringValue && kv.Type != PathValue {
+		return "", fmt.Errorf(
+			"%w: key %q has type %s, not %s",
+			errWrongType,
+			kv.Key,
+			kv.Type,
+			StringValue,
+		)
+	}
+
+	if kv.Value == nil {
+		return "", fmt.Errorf("%w: key %q", errUnset, kv.Key)
+	}
+
+	s, ok := kv.Value.(string)
+	if !ok {
+		return "", newValueMismatchError(kv.Key, kv.Type, kv.Value)
+	}
+
+	return s, nil
+}
+
+// Int returns the value of an [IntValue] as int. The value is also accepted as
+// a float64 if it holds a whole number that can be represented exactly, for
+// example, when it is decoded from JSON without [KeyValue.UnmarshalJSON]. It
+// returns an error if kv is not an IntValue, if the value is nil and thus not
+// set, or if the value cannot be represented exactly as an int on the current
+// platform. Use [KeyValue.Int64] to get the values that do not fit in a 32-bit
+// int portably.
+func (kv KeyValue) Int() (int, error) {
+	if kv.Type != IntValue {
+		return 0, fmt.Errorf(
+			"%w: key %q has type %s, not %s",
+			errWrongType,
+			kv.Key,
+			kv.Type,
+			IntValue,
+		)
+	}
+
+	if kv.Value == nil {
+		return 0, fmt.Errorf("%w: key %q", errUnset, kv.Key)
+	}
+
+	return coerceInt(kv.Key, kv.Value)
+}
+
+// Int64 returns the value of an [IntValue] as int64. It accepts the same
+// values as [KeyValue.Int] but it does not depend on the size of int on
+// the current platform. It returns an error if kv is not an IntValue, if
+// the value is not set, or if the value cannot be represented exactly as
+// an int64.
+func (kv KeyValue) Int64() (int64, error) {
+	if kv.Type != IntValue {
+		return 0, fmt.Errorf(
+			"%w: key %q has type %s, not %s",
+			errWrongType,
+			kv.Key,
+			kv.Type,
+			IntValue,
+		)
+	}
+
+	if kv.Value == nil {
+		return 0, fmt.Errorf("%w: key %q", errUnset, kv.Key)
+	}
+
+	return coerceInt64(kv.Key, kv.Value)
+}
+
+// Uint returns the value of a [UintValue] as uint64. The value is also
+// accepted as a float64 if it holds a non-negative whole number that can be
+// represented exactly. It returns an error if kv is not a UintValue, if
+// the value is not set, or if the value is negative.
+func (kv KeyValue) Uint() (uint64, error) {
+	if kv.Type != UintValue {
+		return 0, fmt.Errorf(
+			"%w: key %q has type %s, not %s",
+			errWrongType,
+			kv.Key,
+			kv.Type,
+			UintValue,
+		)
+	}
+
+	if kv.Value == nil {
+		return 0, fmt.Errorf("%w: key %q", errUnset, kv.Key)
+	}
+
+	return coerceUint(kv.Key, kv.Value)
+}
+
+// Object returns the nested values of an [ObjectValue] as KeyValues in
+// the order of the declared Fields. If the object has no value for a field,
+// the default value of the field is used. It returns an error if kv is not an
+// ObjectValue or if the value is not set. To get the default values of all of
+// the fields for an unset value, set the value to [ValueType.ZeroValue] first.
+func (kv KeyValue) Object() ([]KeyValue, error) {
+	if kv.Type != ObjectValue {
+		return nil, fmt.Errorf("%w: key %q has type %s", errNotObject, kv.Key, kv.Type)
+	}
+
+	if kv.Value == nil {
+		return nil, fmt.Errorf("%w: key %q", errUnset, kv.Key)
+	}
+
+	values, ok := kv.Value.(map[string]any)
+	if !ok {
+		return nil, newValueMismatchError(kv.Key, kv.Type, kv.Value)
+	}
+
+	result := make([]KeyValue, 0, len(kv.Fields))
+
+	for _, field := range kv.Fields {
+		if v, ok := values[field.Key]; ok {
+			field.Value = v
+		}
+
+		result = append(result, field)
+	}
+
+	return result, nil
+}
+
+// MarshalJSON implements [encoding/json.Marshaler]. A nil Value is encoded as
+// the zero value of Type, see [ValueType.ZeroValue], so that the receiver
+// gets a value of the declared type instead of null. The Value of a field in
+// Fields is encoded the same way.
+func (kv KeyValue) MarshalJSON() ([]byte, error) {
+	type keyValue KeyValue
+
+	raw := keyValue(kv)
+	if raw.Value == nil {
+		raw.Value = kv.Type.ZeroValue()
+	}
+
+	data, err := json.Marshal(raw)
+	if err != nil {
+		return nil, fmt.Errorf("%w", err)
+	}
+
+	return data, nil
+}
+
+// MarshalJSON implements [encoding/json.Marshaler]. It is needed as
+// the embedded [KeyValue] has its own MarshalJSON that would otherwise be
+// promoted and used to encode the whole ConfigEntry. Unlike for a KeyValue,
+// a nil Value is encoded as null as it means that the entry has no default
+// value.
+func (e ConfigEntry) MarshalJSON() ([]byte, error) {
+	type configEntry ConfigEntry
+
+	// The MarshalJSON field shadows the method promoted from the embedded
+	// KeyValue so that the standard encoding is used for the entry.
+	aux := struct {
+		*configEntry
+
+		MarshalJSON struct{} `json:"-"`
+	}{configEntry: (*configEntry)(&e), MarshalJSON: struct{}{}}
+
+	data, err := json.Marshal(aux)
+	if err != nil {
+		return nil, fmt.Errorf("%w", err)
+	}
+
+	return data, nil
+}
+
+// UnmarshalJSON implements [encoding/json.Unmarshaler]. In addition to
+// decoding the fields of the KeyValue, it checks that the decoded value
+// matches Type and converts it to the Go type that corresponds to Type. For
+// example, the value of an [IntValue] is stored as int and the value of
+// a [UintValue] as uint64. The integers are parsed from their JSON text and
+// not through float64, so they keep their full precision even above 2^53.
+// The values of an [ObjectValue] are checked recursively against the declared
+// Fields. Values with an unknown Type are left as they are decoded with
+// [encoding/json.Unmarshal].
+func (kv *KeyValue) UnmarshalJSON(data []byte) error {
+	type keyValue KeyValue
+
+	var raw keyValue
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return fmt.Errorf("%w", err)
+	}
+
+	value, err := decodeValue(data)
+	if err != nil {
+		return err
+	}
+
+	v, err := coerceValue(raw.Key, raw.Type, raw.Fields, value)
+	if err != nil {
+		return err
+	}
+
+	raw.Value = v
+	*kv = KeyValue(raw)
+
+	return nil
+}
+
+// UnmarshalJSON implements [encoding/json.Unmarshaler]. It is needed as
+// the embedded [KeyValue] has its own UnmarshalJSON that would otherwise be
+// promoted and used to decode the whole ConfigEntry.
+func (e *ConfigEntry) UnmarshalJSON(data []byte) error {
+	type configEntry ConfigEntry
+
+	// The UnmarshalJSON field shadows the method promoted from the embedded
+	// KeyValue so that the standard decoding is used for the entry.
+	aux := struct {
+		*configEntry
+
+		UnmarshalJSON struct{} `json:"-"`
+	}{configEntry: (*configEntry)(e)}
+
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return fmt.Errorf("%w", err)
+	}
+
+	value, err := decodeValue(data)
+	if err != nil {
+		return err
+	}
+
+	v, err := coerceValue(e.Key, e.Type, e.Fields, value)
+	if err != nil {
+		return err
+	}
+
+	e.Value = v
+
+	return nil
+}
+
+// decodeValue decodes the "value" field of the JSON object in data. The numbers
+// are decoded as json.Number so that the integers can be parsed without
+// the loss of precision.
+func decodeValue(data []byte) (any, error) {
+	var aux struct {
+		Value any `json:"value"`
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+
+	if err := dec.Decode(&aux); err != nil {
+		return nil, fmt.Errorf("%w", err)
+	}
+
+	return aux.Value, nil
+}
+
+// normalizedValue returns the value of kv converted to the Go type that
+// corresponds to Type. If the value cannot be converted, it is returned as is.
+func (kv KeyValue) normalizedValue() any {
+	v, err := coerceValue(kv.Key, kv.Type, kv.Fields, kv.Value)
+	if err != nil {
+		return kv.Value
+	}
+
+	return v
+}
+
+// normalize returns v converted to the Go type that corresponds to the Type of
+// e. If v cannot be converted, it is returned as is.
+func (e ConfigEntry) normalize(v any) any {
+	return KeyValue{Key: e.Key, Value: v, Type: e.Type, Fields: e.Fields}.normalizedValue()
+}
+
+// coerceValue checks that v is a valid value for the given type and converts
+// it to the Go type that corresponds to the type. Nil is always valid as it
+// means that no value is set.
+func coerceValue(key string, t ValueType, fields []KeyValue, v any) (any, error) {
+	if v == nil {
+		return nil, nil //nolint:nilnil // nil is a valid, unset value
+	}
+
+	switch t {
+	case BoolValue:
+		if b, ok := v.(bool); ok {
+			return b, nil
+		}
+	case IntValue:
+		n, err := coerceInt64(key, v)
+		if err != nil {
+			return nil, err
+		}
+
+		// The values that do not fit in int on 32-bit platforms are kept as
+		// int64 so that they can still be read with KeyValue.Int64.
+		if n < math.MinInt || n > math.MaxInt {
+			return n, nil
+		}
+
+		return int(n), nil
+	case UintValue:
+		return coerceUint(key, v)
+	case StringValue, PathValue:
+		if s, ok := v.(string); ok {
+			return s, nil
+		}
+	case ObjectValue:
+		return coerceObject(key, fields, v)
+	default:
+		return plainNumbers(v), nil
+	}
+
+	return nil, newValueMismatchError(key, t, v)
+}
+
+// maxExactFloat is the absolute value of the smallest integer that float64
+// cannot be trusted to represent exactly. The integers above it may be
+// the result of rounding another integer when decoding.
+const maxExactFloat = 1 << 53
+
+// coerceInt converts v to int. The check for overflow is done against the size
+// of int on the current platform.
+func coerceInt(key string, v any) (int, error) {
+	n, err := coerceInt64(key, v)
+	if err != nil {
+		return 0, err
+	}
+
+	if n < math.MinInt || n > math.MaxInt {
+		return 0, fmt.Errorf("%w: key %q: %d", errIntOverflow, key, n)
+	}
+
+	return int(n), nil
+}
+
+// coerceInt64 converts v to int64. All of the Go integer types are accepted
+// so that the values set in Go code do not have to be int.
+func coerceInt64(key string, v any) (int64, error) {
+	switch n := v.(type) {
+	case int, int8, int16, int32, int64:
+		return reflect.ValueOf(n).Int(), nil
+	case uint, uint8, uint16, uint32, uint64, uintptr:
+		u := reflect.ValueOf(n).Uint()
+		if u > math.MaxInt64 {
+			return 0, fmt.Errorf("%w: key %q: %d", errInt64, key, u)
+		}
+
+		return int64(u), nil
+	case json.Number:
+		i, err := strconv.ParseInt(n.String(), 10, 64)
+		if err == nil {
+			return i, nil
+		}
+
+		if errors.Is(err, strconv.ErrRange) {
+			return 0, fmt.Errorf("%w: key %q: %s", errInt64, key, n)
+		}
+
+		// The number is not written as an integer, for example, "1e3" or
+		// "5.0", so it is checked as a float.
+		f, err := n.Float64()
+		if err != nil {
+			return 0, fmt.Errorf("%w: key %q: %s", errIntPrecision, key, n)
+		}
+
+		return coerceInt64(key, f)
+	case float64:
+		if n != math.Trunc(n) {
+			break
+		}
+
+		if math.Abs(n) >= maxExactFloat {
+			return 0, fmt.Errorf("%w: key %q: %g", errIntPrecision, key, n)
+		}
+
+		return int64(n), nil
+	}
+
+	return 0, newValueMismatchError(key, IntValue, v)
+}
+
+// coerceUint converts v to uint64.
+func coerceUint(key string, v any) (uint64, error) {
+	switch n := v.(type) {
+	case uint, uint8, uint16, uint32, uint64, uintptr:
+		return reflect.ValueOf(n).Uint(), nil
+	case int, int8, int16, int32, int64:
+		i := reflect.ValueOf(n).Int()
+		if i < 0 {
+			return 0, fmt.Errorf("%w: key %q: %d", errNegativeUint, key, i)
+		}
+
+		return uint64(i), nil
+	case json.Number:
+		if strings.HasPrefix(n.String(), "-") {
+			return 0, fmt.Errorf("%w: key %q: %s", errNegativeUint, key, n)
+		}
+
+		u, err := strconv.ParseUint(n.String(), 10, 64)
+		if err == nil {
+			return u, nil
+		}
+
+		if errors.Is(err, strconv.ErrRange) {
+			return 0, fmt.Errorf("%w: key %q: %s", errUintRange, key, n)
+		}
+
+		f, err := n.Float64()
+		if err != nil {
+			return 0, fmt.Errorf("%w: key %q: %s", errIntPrecision, key, n)
+		}
+
+		return coerceUint(key, f)
+	case float64:
+		if n != math.Trunc(n) {
+			break
+		}
+
+		if n < 0 {
+			return 0, fmt.Errorf("%w: key %q: %g", errNegativeUint, key, n)
+		}
+
+		if n >= maxExactFloat {
+			return 0, fmt.Errorf("%w: key %q: %g", errIntPrecision, key, n)
+		}
+
+		return uint64(n), nil
+	}
+
+	return 0, newValueMismatchError(key, UintValue, v)
+}
+
+func coerceObject(key string, fields []KeyValue, v any) (any, error) {
+	m, ok := v.(map[string]any)
+	if !ok {
+		return nil, newValueMismatchError(key, ObjectValue, v)
+	}
+
+	result := make(map[string]any, len(m))
+
+	for k, fv := range m {
+		i := indexKeyValue(fields, k)
+		if i < 0 {
+			return nil, fmt.Errorf("%w: key %q: %q", errUnknownField, key, k)
+		}
+
+		f := fields[i]
+
+		cv, err := coerceValue(key+"."+k, f.Type, f.Fields, fv)
+		if err != nil {
+			return nil, err
+		}
+
+		result[k] = cv
+	}
+
+	return result, nil
+}
+
+// newValueMismatchError returns a [ValueMismatchError] for the value v of
+// the given key that does not match the type t.
+func newValueMismatchError(key string, t ValueType, v any) error {
+	return &ValueMismatchError{Key: key, Expected: t, GotType: fmt.Sprintf("%T", v)}
+}
+
+// plainNumbers returns v with the json.Number values within it converted to
+// float64 as they would be decoded without UseNumber. The slices and the maps
+// are copied so that v is not modified.
+func plainNumbers(v any) any {
+	switch x := v.(type) {
+	case json.Number:
+		f, err := x.Float64()
+		if err != nil {
+			return x
+		}
+
+		return f
+	case []any:
+		if x == nil {
+			return x
+		}
+
+		result := make([]any, len(x))
+
+		for i, e := range x {
+			result[i] = plainNumbers(e)
+		}
+
+		return result
+	case map[string]any:
+		if x == nil {
+			return x
+		}
+
+		result := make(map[string]any, len(x))
+
+		for k, e := range x {
+			result[k] = plainNumbers(e)
+		}
+
+		return result
+	default:
+		return v
+	}
+}
+
+// indexKeyValue returns the index of the KeyValue with the given key in kvs or
+// -1 if there is no such KeyValue.
+func indexKeyValue(kvs []KeyValue, key string) int {
+	for i, kv := range kvs {
+		if kv.Key == key {
+			return i
+		}
+	}
+
+	return -1
+}
@@ -0,0 +1,886 @@
+// Copyright 2025 Antti Kivi
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api_test
+
+import (
+	"encoding/json"
+	"errors"
+	"math"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/reginald-project/reginald-sdk-go/api"
+)
+
+const objectKeyValue = `{
+	"key": "server",
+	"type": "object",
+	"value": {"host": "localhost", "port": 8080},
+	"fields": [
+		{"key": "host", "type": "string", "value": ""},
+		{"key": "port", "type": "int", "value": 80},
+		{"key": "tls", "type": "bool", "value": false}
+	]
+}`
+
+func TestKeyValueUnmarshalJSON(t *testing.T) {
+	t.Parallel()
+
+	//nolint:govet // don't care about this in tests
+	for _, test := range []struct {
+		in   string
+		want any
+	}{
+		{`{"key": "a", "type": "bool", "value": true}`, true},
+		{`{"key": "a", "type": "int", "value": 3}`, 3},
+		{`{"key": "a", "type": "int", "value": 3.0}`, 3},
+		{`{"key": "a", "type": "string", "value": "x"}`, "x"},
+		{`{"key": "a", "type": "string"}`, nil},
+		{`{"key": "a", "type": "object", "value": {}}`, map[string]any{}},
+	} {
+		var kv api.KeyValue
+		if err := json.Unmarshal([]byte(test.in), &kv); err != nil {
+			t.Fatalf("%s: %v", test.in, err)
+		}
+
+		if !reflect.DeepEqual(kv.Value, test.want) {
+			t.Errorf("%s: got %#v, want %#v", test.in, kv.Value, test.want)
+		}
+	}
+}
+
+func TestKeyValueUnmarshalJSONError(t *testing.T) {
+	t.Parallel()
+
+	for _, test := range []struct {
+		in   string
+		want string // error string should contain this
+	}{
+		{`{"key": "a", "type": "bool", "value": "true"}`, "does not match"},
+		{`{"key": "a", "type": "int", "value": 3.5}`, "does not match"},
+		{`{"key": "a", "type": "string", "value": 1}`, "does not match"},
+		{`{"key": "a", "type": "object", "value": []}`, "does not match"},
+		{
+			`{"key": "a", "type": "object", "value": {"b": 1},
+				"fields": [{"key": "b", "type": "string"}]}`,
+			`key "a.b"`,
+		},
+		{
+			`{"key": "a", "type": "object", "value": {"c": 1},
+				"fields": [{"key": "b", "type": "int"}]}`,
+			"unknown",
+		},
+	} {
+		var kv api.KeyValue
+
+		err := json.Unmarshal([]byte(test.in), &kv)
+		if err == nil || !strings.Contains(err.Error(), test.want) {
+			t.Errorf("%s: got %v, want string containing %q", test.in, err, test.want)
+		}
+	}
+}
+
+func TestNewKeyValue(t *testing.T) {
+	t.Parallel()
+
+	for _, test := range []struct {
+		v    any
+		want api.KeyValue
+	}{
+		{true, api.KeyValue{Key: "k", Type: api.BoolValue, Value: true}},
+		{7, api.KeyValue{Key: "k", Type: api.IntValue, Value: 7}},
+		{int64(-7), api.KeyValue{Key: "k", Type: api.IntValue, Value: -7}},
+		{int8(7), api.KeyValue{Key: "k", Type: api.IntValue, Value: 7}},
+		{uint(7), api.KeyValue{Key: "k", Type: api.UintValue, Value: uint64(7)}},
+		{uint16(7), api.KeyValue{Key: "k", Type: api.UintValue, Value: uint64(7)}},
+		{"s", api.KeyValue{Key: "k", Type: api.StringValue, Value: "s"}},
+	} {
+		got, err := api.NewKeyValue("k", test.v)
+		if err != nil {
+			t.Fatalf("%#v: %v", test.v, err)
+		}
+
+		if !reflect.DeepEqual(got, test.want) {
+			t.Errorf("%#v: got %#v, want %#v", test.v, got, test.want)
+		}
+
+		kv := api.ConfigEntry{KeyValue: got}
+		if err := kv.ValidateValue(&got); err != nil {
+			t.Errorf("%#v: inconsistent KeyValue: %v", test.v, err)
+		}
+	}
+
+	for _, v := range []any{1.5, nil, []string{"a"}, map[string]any{}} {
+		_, err := api.NewKeyValue("k", v)
+		if err == nil || !strings.Contains(err.Error(), "unsupported value type") {
+			t.Errorf("%#v: got %v, want string containing %q", v, err, "unsupported value type")
+		}
+	}
+}
+
+func TestDecodeKeyValues(t *testing.T) {
+	t.Parallel()
+
+	data := `[
+		{"key": "a", "type": "bool", "value": true},
+		{"key": "b", "type": "int", "value": 7},
+		{"key": "c", "type": "uint", "value": 8},
+		{"key": "d", "type": "string", "value": "s"},
+		` + objectKeyValue + `
+	]`
+
+	kvs, err := api.DecodeKeyValues([]byte(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []any{true, 7, uint64(8), "s", map[string]any{"host": "localhost", "port": 8080}}
+	if len(kvs) != len(want) {
+		t.Fatalf("got %d KeyValues, want %d", len(kvs), len(want))
+	}
+
+	for i, kv := range kvs {
+		if !reflect.DeepEqual(kv.Value, want[i]) {
+			t.Errorf("[%d]: got %#v, want %#v", i, kv.Value, want[i])
+		}
+	}
+}
+
+func TestDecodeKeyValuesError(t *testing.T) {
+	t.Parallel()
+
+	data := `[
+		{"key": "a", "type": "bool", "value": "yes"},
+		{"key": "b", "type": "int", "value": 7},
+		{"key": "c", "type": "uint", "value": -1}
+	]`
+
+	kvs, err := api.DecodeKeyValues([]byte(data))
+	if kvs != nil {
+		t.Errorf("got %v, want nil", kvs)
+	}
+
+	if err == nil {
+		t.Fatal("want error")
+	}
+
+	for _, want := range []string{`[0]: `, `key "a"`, `[2]: `, `key "c"`} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("got %v, want string containing %q", err, want)
+		}
+	}
+
+	if strings.Contains(err.Error(), "[1]") {
+		t.Errorf("got %v, want no error for [1]", err)
+	}
+
+	if _, err := api.DecodeKeyValues([]byte(`{"key": "a"}`)); err == nil {
+		t.Error("decoding an object succeeded, want error")
+	}
+}
+
+func TestKeyValueInt(t *testing.T) {
+	t.Parallel()
+
+	//nolint:govet // don't care about this in tests
+	for _, test := range []struct {
+		kv   api.KeyValue
+		want int
+	}{
+		{api.KeyValue{Key: "a", Type: api.IntValue, Value: 7}, 7},
+		{api.KeyValue{Key: "a", Type: api.IntValue, Value: -7.0}, -7},
+		{api.KeyValue{Key: "a", Type: api.IntValue, Value: int64(7)}, 7},
+		{api.KeyValue{Key: "a", Type: api.IntValue, Value: float64(math.MaxInt32)}, math.MaxInt32},
+	} {
+		got, err := test.kv.Int()
+		if err != nil {
+			t.Fatalf("%#v: %v", test.kv, err)
+		}
+
+		if got != test.want {
+			t.Errorf("%#v: got %d, want %d", test.kv, got, test.want)
+		}
+	}
+}
+
+func TestKeyValueIntError(t *testing.T) {
+	t.Parallel()
+
+	for _, test := range []struct {
+		kv   api.KeyValue
+		want string // error string should contain this
+	}{
+		{api.KeyValue{Key: "a", Type: api.StringValue, Value: "7"}, "wrong value type"},
+		{api.KeyValue{Key: "a", Type: api.IntValue, Value: "7"}, "does not match"},
+		{api.KeyValue{Key: "a", Type: api.IntValue, Value: 7.5}, "does not match"},
+		{api.KeyValue{Key: "big", Type: api.IntValue, Value: float64(1 << 53)}, `key "big"`},
+		{api.KeyValue{Key: "big", Type: api.IntValue, Value: 1e300}, "exactly"},
+	} {
+		_, err := test.kv.Int()
+		if err == nil || !strings.Contains(err.Error(), test.want) {
+			t.Errorf("%#v: got %v, want string containing %q", test.kv, err, test.want)
+		}
+	}
+}
+
+func TestValueMismatchError(t *testing.T) {
+	t.Parallel()
+
+	owner := api.NewConfigEntry(
+		"owner",
+		api.ObjectValue,
+		api.WithFields(api.KeyValue{Key: "uid", Type: api.IntValue}),
+	)
+
+	for _, test := range []struct {
+		err  func() error
+		want api.ValueMismatchError
+	}{
+		{
+			func() error {
+				var kv api.KeyValue
+
+				return json.Unmarshal([]byte(`{"key": "jobs", "type": "int", "value": "4"}`), &kv)
+			},
+			api.ValueMismatchError{Key: "jobs", Expected: api.IntValue, GotType: "string"},
+		},
+		{
+			func() error {
+				_, err := api.DecodeKeyValues(
+					[]byte(`[{"key": "verbose", "type": "bool", "value": 1}]`),
+				)
+
+				return err
+			},
+			api.ValueMismatchError{Key: "verbose", Expected: api.BoolValue, GotType: "json.Number"},
+		},
+		{
+			func() error {
+				_, err := (api.KeyValue{Key: "port", Type: api.UintValue, Value: "80"}).Uint()
+
+				return err
+			},
+			api.ValueMismatchError{Key: "port", Expected: api.UintValue, GotType: "string"},
+		},
+		{
+			func() error { _, err := owner.ParseValue(`{"uid": "root"}`); return err },
+			api.ValueMismatchError{Key: "owner.uid", Expected: api.IntValue, GotType: "string"},
+		},
+		{
+			func() error {
+				return owner.ValidateValue(
+					&api.KeyValue{Key: "owner", Value: []any{}},
+				)
+			},
+			api.ValueMismatchError{
+				Key:      "owner",
+				Expected: api.ObjectValue,
+				GotType:  "[]interface {}",
+			},
+		},
+	} {
+		err := test.err()
+
+		var got *api.ValueMismatchError
+		if !errors.As(err, &got) {
+			t.Errorf("%s: got %v, want a ValueMismatchError", test.want.Key, err)
+
+			continue
+		}
+
+		if *got != test.want {
+			t.Errorf("got %+v, want %+v", *got, test.want)
+		}
+
+		if want := "value does not match type"; !strings.Contains(err.Error(), want) {
+			t.Errorf("got %v, want string containing %q", err, want)
+		}
+	}
+
+	_, err := (api.KeyValue{Key: "a", Type: api.StringValue, Value: "7"}).Int()
+
+	var mismatch *api.ValueMismatchError
+	if errors.As(err, &mismatch) {
+		t.Errorf("got %v, want an error for the wrong accessor", err)
+	}
+}
+
+func TestKeyValueUnset(t *testing.T) {
+	t.Parallel()
+
+	for _, test := range []struct {
+		typ  api.ValueType
+		zero any
+	}{
+		{api.BoolValue, false},
+		{api.IntValue, 0},
+		{api.UintValue, uint64(0)},
+		{api.StringValue, ""},
+		{api.PathValue, ""},
+		{api.ObjectValue, map[string]any{}},
+		{"list", nil},
+	} {
+		kv := api.KeyValue{Key: "a", Type: test.typ}
+
+		if got := test.typ.ZeroValue(); !reflect.DeepEqual(got, test.zero) {
+			t.Errorf("%s: got zero value %#v, want %#v", test.typ, got, test.zero)
+		}
+
+		zero, err := json.Marshal(test.zero)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		want := `"value":` + string(zero) + `,`
+
+		data, err := json.Marshal(kv)
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", test.typ, err)
+		}
+
+		if !strings.Contains(string(data), want) {
+			t.Errorf("%s: got %s, want string containing %q", test.typ, data, want)
+		}
+
+		data, err = kv.AppendJSON(nil)
+		if err != nil || !strings.Contains(string(data), want) {
+			t.Errorf("%s: got %s (%v), want string containing %q", test.typ, data, err, want)
+		}
+
+		var decoded api.KeyValue
+		if err := json.Unmarshal(data, &decoded); err != nil ||
+			!reflect.DeepEqual(decoded.Value, test.zero) {
+			t.Errorf("%s: got %#v (%v), want %#v", test.typ, decoded.Value, err, test.zero)
+		}
+
+		// A nil Value of a ConfigEntry means that it has no default, so it
+		// stays null.
+		data, err = json.Marshal(api.ConfigEntry{KeyValue: kv})
+		if want := `"value":null`; err != nil || !strings.Contains(string(data), want) {
+			t.Errorf("%s: got %s (%v), want string containing %q", test.typ, data, err, want)
+		}
+
+		for _, accessor := range []struct {
+			name  string
+			types []api.ValueType
+			call  func() error
+		}{
+			{
+				"Bool",
+				[]api.ValueType{api.BoolValue},
+				func() error { _, err := kv.Bool(); return err },
+			},
+			{"Int", []api.ValueType{api.IntValue}, func() error { _, err := kv.Int(); return err }},
+			{
+				"Int64",
+				[]api.ValueType{api.IntValue},
+				func() error { _, err := kv.Int64(); return err },
+			},
+			{
+				"Uint",
+				[]api.ValueType{api.UintValue},
+				func() error { _, err := kv.Uint(); return err },
+			},
+			{
+				"Str",
+				[]api.ValueType{api.StringValue, api.PathValue},
+				func() error { _, err := kv.Str(); return err },
+			},
+			{
+				"Object",
+				[]api.ValueType{api.ObjectValue},
+				func() error { _, err := kv.Object(); return err },
+			},
+		} {
+			err := accessor.call()
+
+			if !slices.Contains(accessor.types, test.typ) {
+				if err == nil {
+					t.Errorf(
+						"%s: %s: got nil error, want error for the wrong type",
+						test.typ,
+						accessor.name,
+					)
+				}
+
+				continue
+			}
+
+			if err == nil || !strings.Contains(err.Error(), `value is not set: key "a"`) {
+				t.Errorf(
+					"%s: %s: got %v, want string containing %q",
+					test.typ,
+					accessor.name,
+					err,
+					"value is not set",
+				)
+			}
+		}
+	}
+
+	owner := api.KeyValue{
+		Key:    "owner",
+		Type:   api.ObjectValue,
+		Fields: []api.KeyValue{{Key: "name", Type: api.StringValue, Value: "root"}},
+	}
+	owner.Value = owner.Type.ZeroValue()
+
+	got, err := owner.Object()
+	if want := owner.Fields; err != nil || !reflect.DeepEqual(got, want) {
+		t.Errorf("got %+v (%v), want the default fields %+v", got, err, want)
+	}
+}
+
+func TestKeyValueBoolStr(t *testing.T) {
+	t.Parallel()
+
+	if got, err := api.NewBoolKeyValue("a", true).Bool(); err != nil || !got {
+		t.Errorf("Bool: got %t (%v), want true", got, err)
+	}
+
+	if got, err := api.NewStringKeyValue("a", "x").Str(); err != nil || got != "x" {
+		t.Errorf("Str: got %q (%v), want %q", got, err, "x")
+	}
+
+	path := api.KeyValue{Key: "a", Value: "/tmp", Type: api.PathValue}
+	if got, err := path.Str(); err != nil || got != "/tmp" {
+		t.Errorf("Str: got %q (%v), want %q", got, err, "/tmp")
+	}
+
+	var mismatch *api.ValueMismatchError
+
+	if _, err := (api.KeyValue{
+		Key:   "a",
+		Value: "yes",
+		Type:  api.BoolValue,
+	}).Bool(); !errors.As(err, &mismatch) {
+		t.Errorf("Bool: got %v, want a ValueMismatchError", err)
+	}
+
+	if _, err := (api.KeyValue{
+		Key:   "a",
+		Value: 1,
+		Type:  api.StringValue,
+	}).Str(); !errors.As(err, &mismatch) {
+		t.Errorf("Str: got %v, want a ValueMismatchError", err)
+	}
+}
+
+func TestKeyValueUnmarshalJSONInt64(t *testing.T) {
+	t.Parallel()
+
+	for _, test := range []struct {
+		in   string
+		want int64
+	}{
+		{"9007199254740993", 9007199254740993},
+		{"-9007199254740993", -9007199254740993},
+		{"9223372036854775807", math.MaxInt64},
+		{"2147483648", math.MaxInt32 + 1},
+		{"1e3", 1000},
+		{"5.0", 5},
+	} {
+		in := `{"key":"id","value":` + test.in + `,"type":"int"}`
+
+		var kv api.KeyValue
+		if err := json.Unmarshal([]byte(in), &kv); err != nil {
+			t.Fatalf("%s: %v", in, err)
+		}
+
+		got, err := kv.Int64()
+		if err != nil {
+			t.Fatalf("%s: %v", in, err)
+		}
+
+		if got != test.want {
+			t.Errorf("%s: got %d, want %d", in, got, test.want)
+		}
+
+		n, err := kv.Int()
+
+		switch {
+		case strconv.IntSize == 32 && (test.want < math.MinInt32 || test.want > math.MaxInt32):
+			if err == nil || !strings.Contains(err.Error(), "overflows") {
+				t.Errorf("%s: got %v, want overflow error", in, err)
+			}
+		case err != nil || int64(n) != test.want:
+			t.Errorf("%s: got %d, %v, want %d", in, n, err, test.want)
+		}
+
+		if !strings.Contains(test.in, ".") && !strings.Contains(test.in, "e") {
+			out, err := json.Marshal(kv)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if string(out) != in {
+				t.Errorf("got %s, want %s", out, in)
+			}
+		}
+	}
+}
+
+func TestKeyValueUnmarshalJSONIntError(t *testing.T) {
+	t.Parallel()
+
+	for _, test := range []struct {
+		in   string
+		want string // error string should contain this
+	}{
+		{`{"key": "id", "type": "int", "value": 9223372036854775808}`, `overflows int64: key "id"`},
+		{`{"key": "id", "type": "int", "value": 1e19}`, `key "id"`},
+		{`{"key": "id", "type": "int", "value": 1.5}`, "does not match"},
+	} {
+		var kv api.KeyValue
+
+		err := json.Unmarshal([]byte(test.in), &kv)
+		if err == nil || !strings.Contains(err.Error(), test.want) {
+			t.Errorf("%s: got %v, want string containing %q", test.in, err, test.want)
+		}
+	}
+}
+
+func TestKeyValueGoIntegerKinds(t *testing.T) {
+	t.Parallel()
+
+	kinds := []any{
+		int8(7), int16(7), int32(7), int64(7), uint(7), uint8(7), uint16(7), uint32(7), uint64(7),
+	}
+
+	for _, v := range kinds {
+		kv := api.KeyValue{Key: "n", Type: api.IntValue, Value: v}
+
+		if n, err := kv.Int64(); err != nil || n != 7 {
+			t.Errorf("%T: Int64: got %d, %v, want 7", v, n, err)
+		}
+
+		e := api.ConfigEntry{KeyValue: kv}
+		if err := e.ValidateValue(&kv); err != nil || kv.Value != 7 {
+			t.Errorf("%T: ValidateValue: got %#v, %v, want 7", v, kv.Value, err)
+		}
+
+		kv = api.KeyValue{Key: "n", Type: api.UintValue, Value: v}
+		if n, err := kv.Uint(); err != nil || n != 7 {
+			t.Errorf("%T: Uint: got %d, %v, want 7", v, n, err)
+		}
+	}
+
+	_, err := api.KeyValue{Key: "n", Type: api.IntValue, Value: uint64(math.MaxUint64)}.Int64()
+	if err == nil || !strings.Contains(err.Error(), "overflows int64") {
+		t.Errorf("got %v, want overflow error", err)
+	}
+
+	_, err = api.KeyValue{Key: "n", Type: api.UintValue, Value: int8(-1)}.Uint()
+	if err == nil || !strings.Contains(err.Error(), "negative") {
+		t.Errorf("got %v, want negative error", err)
+	}
+}
+
+func TestKeyValueUint(t *testing.T) {
+	t.Parallel()
+
+	for _, test := range []struct {
+		in   string
+		want uint64
+	}{
+		{`{"key": "port", "type": "uint", "value": 8080}`, 8080},
+		{`{"key": "port", "type": "uint", "value": 0}`, 0},
+		{`{"key": "port", "type": "uint", "value": 9007199254740991}`, 1<<53 - 1},
+		{`{"key": "port", "type": "uint", "value": 18446744073709551615}`, math.MaxUint64},
+	} {
+		var kv api.KeyValue
+		if err := json.Unmarshal([]byte(test.in), &kv); err != nil {
+			t.Fatalf("%s: %v", test.in, err)
+		}
+
+		got, err := kv.Uint()
+		if err != nil {
+			t.Fatalf("%s: %v", test.in, err)
+		}
+
+		if got != test.want {
+			t.Errorf("%s: got %d, want %d", test.in, got, test.want)
+		}
+	}
+}
+
+func TestKeyValueUintError(t *testing.T) {
+	t.Parallel()
+
+	for _, test := range []struct {
+		in   string
+		want string // error string should contain this
+	}{
+		{`{"key": "port", "type": "uint", "value": -1}`, "negative"},
+		{`{"key": "port", "type": "uint", "value": 1.5}`, "does not match"},
+		{`{"key": "port", "type": "uint", "value": "1"}`, "does not match"},
+		{`{"key": "port", "type": "uint", "value": 18446744073709551616}`, "overflows uint64"},
+		{`{"key": "port", "type": "uint", "value": 1e20}`, "exactly"},
+	} {
+		var kv api.KeyValue
+
+		err := json.Unmarshal([]byte(test.in), &kv)
+		if err == nil || !strings.Contains(err.Error(), test.want) {
+			t.Errorf("%s: got %v, want string containing %q", test.in, err, test.want)
+		}
+	}
+
+	_, err := api.KeyValue{Key: "port", Type: api.IntValue, Value: 1}.Uint()
+	if err == nil || !strings.Contains(err.Error(), "wrong value type") {
+		t.Errorf("got %v, want wrong value type error", err)
+	}
+}
+
+func TestKeyValuesToMap(t *testing.T) {
+	t.Parallel()
+
+	kvs := []api.KeyValue{
+		{Key: "b", Type: api.IntValue, Value: 1},
+		{Key: "a", Type: api.StringValue, Value: "x"},
+	}
+
+	m, err := api.KeyValuesToMap(kvs)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(m) != 2 || m["a"].Value != "x" || m["b"].Value != 1 {
+		t.Errorf("got %v", m)
+	}
+
+	got := api.MapToKeyValues(m)
+	want := []api.KeyValue{kvs[1], kvs[0]}
+
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+
+	_, err = api.KeyValuesToMap(append(kvs, api.KeyValue{Key: "a", Type: api.BoolValue}))
+	if err == nil || !strings.Contains(err.Error(), `duplicate key: "a"`) {
+		t.Errorf("got %v, want duplicate key error", err)
+	}
+}
+
+func TestKeyValueEqual(t *testing.T) {
+	t.Parallel()
+
+	fields := []api.KeyValue{{Key: "n", Type: api.IntValue}}
+
+	//nolint:govet // don't care about this in tests
+	for _, test := range []struct {
+		a, b api.KeyValue
+		want bool
+	}{
+		{
+			api.KeyValue{Key: "a", Type: api.IntValue, Value: 5},
+			api.KeyValue{Key: "a", Type: api.IntValue, Value: 5.0},
+			true,
+		},
+		{
+			api.KeyValue{Key: "a", Type: api.IntValue, Value: 5},
+			api.KeyValue{Key: "a", Type: api.IntValue, Value: 6.0},
+			false,
+		},
+		{
+			api.KeyValue{Key: "a", Type: api.IntValue, Value: 5},
+			api.KeyValue{Key: "b", Type: api.IntValue, Value: 5},
+			false,
+		},
+		{
+			api.KeyValue{Key: "a", Type: api.StringValue, Value: "5"},
+			api.KeyValue{Key: "a", Type: api.IntValue, Value: 5},
+			false,
+		},
+		{
+			api.KeyValue{Key: "a", Type: api.BoolValue},
+			api.KeyValue{Key: "a", Type: api.BoolValue},
+			true,
+		},
+		{
+			api.KeyValue{Key: "a", Type: api.BoolValue, Value: false},
+			api.KeyValue{Key: "a", Type: api.BoolValue},
+			false,
+		},
+		{
+			api.KeyValue{
+				Key:    "a",
+				Type:   api.ObjectValue,
+				Value:  map[string]any{"n": 1},
+				Fields: fields,
+			},
+			api.KeyValue{
+				Key:    "a",
+				Type:   api.ObjectValue,
+				Value:  map[string]any{"n": 1.0},
+				Fields: fields,
+			},
+			true,
+		},
+		{
+			api.KeyValue{
+				Key:    "a",
+				Type:   api.ObjectValue,
+				Value:  map[string]any{"n": 1},
+				Fields: fields,
+			},
+			api.KeyValue{
+				Key:    "a",
+				Type:   api.ObjectValue,
+				Value:  map[string]any{"n": 2.0},
+				Fields: fields,
+			},
+			false,
+		},
+	} {
+		if got := test.a.Equal(test.b); got != test.want {
+			t.Errorf("%#v.Equal(%#v): got %t, want %t", test.a, test.b, got, test.want)
+		}
+
+		if got := test.b.Equal(test.a); got != test.want {
+			t.Errorf("%#v.Equal(%#v): got %t, want %t", test.b, test.a, got, test.want)
+		}
+	}
+}
+
+func TestKeyValueEqualNoMutation(t *testing.T) {
+	t.Parallel()
+
+	value := map[string]any{"n": json.Number("1"), "list": []any{json.Number("2")}}
+	a := api.KeyValue{Key: "a", Type: "custom", Value: value}
+	b := api.KeyValue{
+		Key:   "a",
+		Type:  "custom",
+		Value: map[string]any{"n": 1.0, "list": []any{2.0}},
+	}
+
+	if !a.Equal(b) {
+		t.Errorf("%#v.Equal(%#v): got false, want true", a, b)
+	}
+
+	want := map[string]any{"n": json.Number("1"), "list": []any{json.Number("2")}}
+	if !reflect.DeepEqual(value, want) {
+		t.Errorf("Equal modified the value: got %#v, want %#v", value, want)
+	}
+}
+
+func TestConfigEntryEqual(t *testing.T) {
+	t.Parallel()
+
+	limit := 10.0
+
+	base := func() api.ConfigEntry {
+		return api.ConfigEntry{
+			KeyValue:      api.KeyValue{Key: "a", Type: api.IntValue, Value: 5},
+			Flag:          &api.Flag{Name: "a"},
+			AllowedValues: []any{5, 6},
+		}
+	}
+
+	//nolint:govet // don't care about this in tests
+	for _, test := range []struct {
+		name   string
+		modify func(e *api.ConfigEntry)
+		want   bool
+	}{
+		{"same", func(*api.ConfigEntry) {}, true},
+		{"value type", func(e *api.ConfigEntry) { e.Value = 5.0 }, true},
+		{"allowed types", func(e *api.ConfigEntry) { e.AllowedValues = []any{5.0, 6.0} }, true},
+		{"value", func(e *api.ConfigEntry) { e.Value = 6 }, false},
+		{"allowed", func(e *api.ConfigEntry) { e.AllowedValues = []any{5} }, false},
+		{"flag", func(e *api.ConfigEntry) { e.Flag = &api.Flag{Name: "b"} }, false},
+		{"no flag", func(e *api.ConfigEntry) { e.Flag = nil }, false},
+		{"required", func(e *api.ConfigEntry) { e.Required = true }, false},
+		{"pattern", func(e *api.ConfigEntry) { e.Pattern = "^a$" }, false},
+		{"max", func(e *api.ConfigEntry) { e.Max = &limit }, false},
+	} {
+		a, b := base(), base()
+		test.modify(&b)
+
+		if got := a.Equal(b); got != test.want {
+			t.Errorf("%s: got %t, want %t", test.name, got, test.want)
+		}
+
+		if got := b.Equal(a); got != test.want {
+			t.Errorf("%s: reversed: got %t, want %t", test.name, got, test.want)
+		}
+	}
+}
+
+func TestKeyValueObject(t *testing.T) {
+	t.Parallel()
+
+	var kv api.KeyValue
+	if err := json.Unmarshal([]byte(objectKeyValue), &kv); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := kv.Object()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []api.KeyValue{
+		{Key: "host", Type: api.StringValue, Value: "localhost"},
+		{Key: "port", Type: api.IntValue, Value: 8080},
+		{Key: "tls", Type: api.BoolValue, Value: false},
+	}
+
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %#v, want %#v", got, want)
+	}
+
+	if _, err := (api.KeyValue{Key: "a", Type: api.IntValue}).Object(); err == nil {
+		t.Error("expected an error for a non-object KeyValue")
+	}
+}
+
+func TestConfigEntryUnmarshalJSON(t *testing.T) {
+	t.Parallel()
+
+	in := `{
+		"key": "count",
+		"type": "int",
+		"value": 2,
+		"flag": {"name": "count", "shorthand": "c", "description": "Count."},
+		"envOverride": "COUNT",
+		"flagOnly": true
+	}`
+
+	var got api.ConfigEntry
+	if err := json.Unmarshal([]byte(in), &got); err != nil {
+		t.Fatal(err)
+	}
+
+	want := api.ConfigEntry{
+		KeyValue:    api.KeyValue{Key: "count", Type: api.IntValue, Value: 2},
+		Flag:        &api.Flag{Name: "count", Shorthand: "c", Description: "Count."},
+		EnvOverride: "COUNT",
+		FlagOnly:    true,
+	}
+
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %#v, want %#v", got, want)
+	}
+
+	var bad api.ConfigEntry
+
+	in = `{"key": "a", "type": "int", "value": "x"}`
+	if err := json.Unmarshal([]byte(in), &bad); err == nil {
+		t.Error("expected an error for a mismatched value")
+	}
+}
@@ -0,0 +1,134 @@
+// Copyright 2025 Antti Kivi
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// Errors returned by the version and URL handling.
+var (
+	errInvalidURL     = errors.New("invalid URL")
+	errInvalidVersion = errors.New("invalid semantic version")
+)
+
+// SemVer parses the Version of the plugin as a semantic version and returns
+// its major, minor, and patch numbers. The pre-release and build metadata are
+// validated but not returned. It returns an error if Version is not a valid
+// semantic version, including when it is empty.
+//
+//nolint:nonamedreturns // names document the result
+func (m *Manifest) SemVer() (major, minor, patch int, err error) {
+	return parseSemVer(m.Version)
+}
+
+// parseSemVer parses s as a semantic version as specified in
+// https://semver.org.
+func parseSemVer(s string) (int, int, int, error) {
+	core, build, hasBuild := strings.Cut(s, "+")
+	if hasBuild && !validIdentifiers(build, false) {
+		return 0, 0, 0, fmt.Errorf("%w: %q: invalid build metadata", errInvalidVersion, s)
+	}
+
+	core, pre, hasPre := strings.Cut(core, "-")
+	if hasPre && !validIdentifiers(pre, true) {
+		return 0, 0, 0, fmt.Errorf("%w: %q: invalid pre-release", errInvalidVersion, s)
+	}
+
+	parts := strings.Split(core, ".")
+	if len(parts) != 3 { //nolint:mnd // major, minor, and patch
+		return 0, 0, 0, fmt.Errorf("%w: %q: want MAJOR.MINOR.PATCH", errInvalidVersion, s)
+	}
+
+	var nums [3]int
+
+	for i, p := range parts {
+		if !numericIdentifier(p) {
+			return 0, 0, 0, fmt.Errorf("%w: %q: invalid number %q", errInvalidVersion, s, p)
+		}
+
+		n, err := strconv.Atoi(p)
+		if err != nil {
+			return 0, 0, 0, fmt.Errorf("%w: %q: %w", errInvalidVersion, s, err)
+		}
+
+		nums[i] = n
+	}
+
+	return nums[0], nums[1], nums[2], nil
+}
+
+// validIdentifiers reports whether s is a valid dot-separated list of
+// pre-release or build identifiers. The numeric pre-release identifiers must
+// not have leading zeros.
+func validIdentifiers(s string, pre bool) bool {
+	for id := range strings.SplitSeq(s, ".") {
+		if id == "" {
+			return false
+		}
+
+		numeric := true
+
+		for _, r := range id {
+			switch {
+			case r >= '0' && r <= '9':
+			case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r == '-':
+				numeric = false
+			default:
+				return false
+			}
+		}
+
+		if pre && numeric && !numericIdentifier(id) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// numericIdentifier reports whether s is a non-empty string of digits without
+// leading zeros.
+func numericIdentifier(s string) bool {
+	if s == "" || (len(s) > 1 && s[0] == '0') {
+		return false
+	}
+
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+
+	return true
+}
+
+// validateURL checks that s is an absolute HTTP or HTTPS URL.
+func validateURL(s string) error {
+	u, err := url.Parse(s)
+	if err != nil {
+		return fmt.Errorf("%w: %w", errInvalidURL, err)
+	}
+
+	if (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("%w: %q: want an absolute HTTP or HTTPS URL", errInvalidURL, s)
+	}
+
+	return nil
+}
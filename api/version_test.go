@@ -0,0 +1,66 @@
+// Copyright 2025 Antti Kivi
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api_test
+
+import (
+	"testing"
+
+	"github.com/reginald-project/reginald-sdk-go/api"
+)
+
+func TestManifestSemVer(t *testing.T) {
+	t.Parallel()
+
+	for _, test := range []struct {
+		version             string
+		major, minor, patch int
+		wantErr             bool
+	}{
+		{"1.2.3", 1, 2, 3, false},
+		{"0.10.0-beta.2", 0, 10, 0, false},
+		{"2.0.0+sha.abc", 2, 0, 0, false},
+		{"", 0, 0, 0, true},
+		{"1.2.x", 0, 0, 0, true},
+		{"1.2.3.4", 0, 0, 0, true},
+	} {
+		m := &api.Manifest{Version: test.version}
+
+		major, minor, patch, err := m.SemVer()
+		if test.wantErr {
+			if err == nil {
+				t.Errorf("%q: want error, got %d.%d.%d", test.version, major, minor, patch)
+			}
+
+			continue
+		}
+
+		if err != nil {
+			t.Fatalf("%q: %v", test.version, err)
+		}
+
+		if major != test.major || minor != test.minor || patch != test.patch {
+			t.Errorf(
+				"%q: got %d.%d.%d, want %d.%d.%d",
+				test.version,
+				major,
+				minor,
+				patch,
+				test.major,
+				test.minor,
+				test.patch,
+			)
+		}
+	}
+}
@@ -0,0 +1,200 @@
+// Copyright 2025 Antti Kivi
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logs
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+// DefaultBufferSize is the buffer size used by [BufferedHandler] if
+// BufferOptions.Size is not set.
+const DefaultBufferSize = 4096
+
+// Errors returned by BufferedHandler.
+var errClosed = errors.New("logs: handler is closed")
+
+// BufferOptions are the options for a BufferedHandler. A zero BufferOptions
+// consists entirely of the default values.
+type BufferOptions struct {
+	HandlerOptions
+
+	// Size is the number of bytes that are buffered before the records are
+	// written to the underlying writer. If Size is zero or negative,
+	// [DefaultBufferSize] is used.
+	Size int
+
+	// FlushInterval is the maximum time a record stays in the buffer before it
+	// is written. If FlushInterval is zero or negative, the records are only
+	// written when the buffer is full or when the handler is flushed or closed.
+	FlushInterval time.Duration
+}
+
+// BufferedHandler is a [Handler] that buffers the formatted records and writes
+// them to the underlying writer in batches. The records are written in the
+// order they were handled when the buffer reaches its size threshold, when the
+// flush interval passes, or when [BufferedHandler.Flush] or
+// [BufferedHandler.Close] is called. The handlers returned by WithAttrs and
+// WithGroup share the buffer with the handler they were created from. If
+// the underlying writer fails, the records that were not written stay in
+// the buffer and are written again on the next flush.
+type BufferedHandler struct {
+	*Handler
+
+	buf *buffer
+}
+
+// buffer is the io.Writer that collects the output of a BufferedHandler.
+type buffer struct {
+	mu       sync.Mutex
+	w        io.Writer
+	data     []byte
+	size     int
+	interval time.Duration
+	timer    *time.Timer
+	err      error // error from a flush run by the timer
+	closed   bool
+}
+
+// NewBufferedHandler returns a new [BufferedHandler] that writes to w using the
+// given options. If opts is nil, the default options are used. The handler
+// must be closed with [BufferedHandler.Close] to write the remaining records.
+func NewBufferedHandler(w io.Writer, opts *BufferOptions) *BufferedHandler {
+	var o BufferOptions
+
+	if opts != nil {
+		o = *opts
+	}
+
+	if o.Size <= 0 {
+		o.Size = DefaultBufferSize
+	}
+
+	b := &buffer{ //nolint:exhaustruct // the rest have zero values
+		w:        w,
+		data:     make([]byte, 0, o.Size),
+		size:     o.Size,
+		interval: o.FlushInterval,
+	}
+
+	return &BufferedHandler{Handler: NewHandler(b, &o.HandlerOptions), buf: b}
+}
+
+// Close writes the buffered records and stops the handler. The records handled
+// after Close return an error.
+func (h *BufferedHandler) Close() error {
+	h.buf.mu.Lock()
+	defer h.buf.mu.Unlock()
+
+	err := h.buf.flushLocked()
+	h.buf.closed = true
+
+	return err
+}
+
+// Flush writes the buffered records to the underlying writer.
+func (h *BufferedHandler) Flush() error {
+	h.buf.mu.Lock()
+	defer h.buf.mu.Unlock()
+
+	return h.buf.flushLocked()
+}
+
+// WithAttrs returns a new [BufferedHandler] whose output has the given
+// attributes added after the attributes of h.
+func (h *BufferedHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	handler, _ := h.Handler.WithAttrs(attrs).(*Handler) //nolint:errcheck // always a *Handler
+
+	return &BufferedHandler{Handler: handler, buf: h.buf}
+}
+
+// WithGroup returns a new [BufferedHandler] that nests the attributes added
+// after it under the given group name.
+func (h *BufferedHandler) WithGroup(name string) slog.Handler {
+	handler, _ := h.Handler.WithGroup(name).(*Handler) //nolint:errcheck // always a *Handler
+
+	return &BufferedHandler{Handler: handler, buf: h.buf}
+}
+
+// Write adds p to the buffer and writes the buffer if it is full. If a flush
+// run by the timer has failed since the last write, its error is returned
+// after p has been added to the buffer, so the record is not lost.
+func (b *buffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.closed {
+		return 0, errClosed
+	}
+
+	b.data = append(b.data, p...)
+	err := b.err
+	b.err = nil
+
+	if len(b.data) >= b.size {
+		if ferr := b.flushLocked(); ferr != nil {
+			return len(p), errors.Join(err, ferr)
+		}
+	}
+
+	if b.interval > 0 && b.timer == nil && len(b.data) > 0 {
+		b.timer = time.AfterFunc(b.interval, b.flushTimer)
+	}
+
+	return len(p), err
+}
+
+// flushTimer is run by the timer of the buffer. As there is no caller to
+// return the error to, it is returned by the next write.
+func (b *buffer) flushTimer() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.timer = nil
+
+	if err := b.flushLocked(); err != nil {
+		b.err = err
+	}
+}
+
+// flushLocked writes the buffered data. The caller must hold the lock. If
+// the write fails, the data that was not written is kept in the buffer and
+// written again by the next flush.
+func (b *buffer) flushLocked() error {
+	if b.timer != nil {
+		b.timer.Stop()
+		b.timer = nil
+	}
+
+	if len(b.data) == 0 {
+		return nil
+	}
+
+	n, err := b.w.Write(b.data)
+	if err != nil {
+		n = max(min(n, len(b.data)), 0)
+		b.data = b.data[:copy(b.data, b.data[n:])]
+
+		return fmt.Errorf("failed to write buffered log records: %w", err)
+	}
+
+	b.data = b.data[:0]
+
+	return nil
+}
@@ -0,0 +1,300 @@
+// Copyright 2025 Antti Kivi
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logs_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/reginald-project/reginald-sdk-go/logs"
+)
+
+var errWrite = errors.New("write failed")
+
+// syncBuffer is a bytes.Buffer that is safe for concurrent use and counts the
+// writes to it.
+type syncBuffer struct {
+	mu     sync.Mutex
+	buf    bytes.Buffer
+	writes int
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.writes++
+
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return b.buf.String()
+}
+
+func (b *syncBuffer) Writes() int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return b.writes
+}
+
+// failWriter is a syncBuffer that fails the writes while fail is set.
+type failWriter struct {
+	syncBuffer
+
+	fail     bool
+	attempts int
+}
+
+func (w *failWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	w.attempts++
+	fail := w.fail
+	w.mu.Unlock()
+
+	if fail {
+		return 0, errWrite
+	}
+
+	return w.syncBuffer.Write(p)
+}
+
+func (w *failWriter) setFail(fail bool) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.fail = fail
+}
+
+func (w *failWriter) Attempts() int {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	return w.attempts
+}
+
+func TestBufferedHandlerFlush(t *testing.T) {
+	t.Parallel()
+
+	var w syncBuffer
+
+	h := logs.NewBufferedHandler(&w, nil)
+	logger := slog.New(h)
+
+	logger.Info("one")
+	logger.Info("two")
+
+	if got := w.Writes(); got != 0 {
+		t.Fatalf("got %d writes before Flush, want 0", got)
+	}
+
+	if err := h.Flush(); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := w.Writes(); got != 1 {
+		t.Errorf("got %d writes after Flush, want 1", got)
+	}
+
+	if got := strings.Count(w.String(), "\n"); got != 2 {
+		t.Errorf("got %d records, want 2", got)
+	}
+
+	logger.With("a", 1).Info("three")
+
+	if err := h.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := strings.Count(w.String(), "\n"); got != 3 {
+		t.Errorf("got %d records after Close, want 3", got)
+	}
+
+	r := slog.NewRecord(testTime, slog.LevelInfo, "closed", 0)
+	if err := h.Handle(context.Background(), r); err == nil {
+		t.Error("Handle after Close succeeded, want error")
+	}
+}
+
+func TestBufferedHandlerSize(t *testing.T) {
+	t.Parallel()
+
+	var w syncBuffer
+
+	h := logs.NewBufferedHandler(&w, &logs.BufferOptions{Size: 1})
+	slog.New(h).Info("hello")
+
+	if got := w.Writes(); got != 1 {
+		t.Errorf("got %d writes, want 1", got)
+	}
+}
+
+func TestBufferedHandlerInterval(t *testing.T) {
+	t.Parallel()
+
+	var w syncBuffer
+
+	h := logs.NewBufferedHandler(&w, &logs.BufferOptions{FlushInterval: time.Millisecond})
+	slog.New(h).Info("hello")
+
+	deadline := time.Now().Add(5 * time.Second)
+	for w.Writes() == 0 {
+		if time.Now().After(deadline) {
+			t.Fatal("records were not flushed by the timer")
+		}
+
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestBufferedHandlerWriteError(t *testing.T) {
+	t.Parallel()
+
+	w := &failWriter{fail: true}
+	h := logs.NewBufferedHandler(w, &logs.BufferOptions{FlushInterval: time.Millisecond})
+
+	r := slog.NewRecord(testTime, slog.LevelInfo, "one", 0)
+	if err := h.Handle(context.Background(), r); err != nil {
+		t.Fatal(err)
+	}
+
+	deadline := time.Now().Add(5 * time.Second)
+	for w.Attempts() == 0 {
+		if time.Now().After(deadline) {
+			t.Fatal("records were not flushed by the timer")
+		}
+
+		time.Sleep(time.Millisecond)
+	}
+
+	r = slog.NewRecord(testTime, slog.LevelInfo, "two", 0)
+	if err := h.Handle(context.Background(), r); !errors.Is(err, errWrite) {
+		t.Errorf("got %v, want the error of the failed flush", err)
+	}
+
+	if err := h.Flush(); !errors.Is(err, errWrite) {
+		t.Errorf("got %v, want %v", err, errWrite)
+	}
+
+	w.setFail(false)
+
+	if err := h.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	out := w.String()
+	if !strings.Contains(out, `"msg":"one"`) || !strings.Contains(out, `"msg":"two"`) {
+		t.Errorf("got %q, want both records", out)
+	}
+
+	if got := strings.Count(out, "\n"); got != 2 {
+		t.Errorf("got %d records, want 2", got)
+	}
+}
+
+func TestBufferedHandlerConcurrent(t *testing.T) {
+	t.Parallel()
+
+	const goroutines, records = 8, 100
+
+	var (
+		w  syncBuffer
+		wg sync.WaitGroup
+	)
+
+	h := logs.NewBufferedHandler(&w, &logs.BufferOptions{Size: 256})
+
+	for g := range goroutines {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			logger := slog.New(h).With("g", g)
+			for i := range records {
+				logger.Info("msg", "i", i)
+			}
+		}()
+	}
+
+	wg.Wait()
+
+	if err := h.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	next := make(map[int]int)
+	lines := strings.Split(strings.TrimSuffix(w.String(), "\n"), "\n")
+
+	if len(lines) != goroutines*records {
+		t.Fatalf("got %d records, want %d", len(lines), goroutines*records)
+	}
+
+	for _, line := range lines {
+		var rec struct{ G, I int }
+
+		if err := json.Unmarshal([]byte(line), &rec); err != nil {
+			t.Fatalf("invalid record %q: %v", line, err)
+		}
+
+		if rec.I != next[rec.G] {
+			t.Fatalf("goroutine %d: got record %d, want %d", rec.G, rec.I, next[rec.G])
+		}
+
+		next[rec.G]++
+	}
+}
+
+func BenchmarkHandler(b *testing.B) {
+	f, err := os.OpenFile(os.DevNull, os.O_WRONLY, 0)
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	b.Cleanup(func() { f.Close() })
+
+	b.Run("unbuffered", func(b *testing.B) {
+		logger := slog.New(logs.NewHandler(f, nil))
+
+		for b.Loop() {
+			logger.Info("hello", "n", 1)
+		}
+	})
+
+	b.Run("buffered", func(b *testing.B) {
+		h := logs.NewBufferedHandler(f, nil)
+		logger := slog.New(h)
+
+		for b.Loop() {
+			logger.Info("hello", "n", 1)
+		}
+
+		if err := h.Close(); err != nil {
+			b.Fatal(err)
+		}
+	})
+}
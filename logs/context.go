@@ -0,0 +1,46 @@
+// Copyright 2025 Antti Kivi
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logs
+
+import (
+	"context"
+	"log/slog"
+)
+
+// RunIDKey is the key of the attribute that holds the ID of the Reginald run
+// that the record belongs to. Reginald uses it to correlate the logs of
+// the plugin invocations within a single run.
+const RunIDKey = "runId"
+
+// RunIDContextKey is the context key for the run ID set by [ContextWithRunID].
+// Add it to HandlerOptions.ContextKeys to log the run ID from the context.
+const RunIDContextKey ContextKey = RunIDKey
+
+// ContextKey is the type of the context keys whose values [Handler] can add to
+// the records. The string value of the key is used as the key of the logged
+// attribute. See HandlerOptions.ContextKeys.
+type ContextKey string
+
+// WithRunID returns a logger that adds the given run ID to every record as
+// [RunIDKey].
+func WithRunID(logger *slog.Logger, id string) *slog.Logger {
+	return logger.With(RunIDKey, id)
+}
+
+// ContextWithRunID returns a copy of ctx that carries the given run ID as
+// the value of [RunIDContextKey].
+func ContextWithRunID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, RunIDContextKey, id)
+}
@@ -0,0 +1,167 @@
+// Copyright 2025 Antti Kivi
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logs
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+)
+
+// FrameMarker is the byte that starts every frame written by the handler
+// returned by [NewFramedHandler]. It is the ASCII record separator.
+const FrameMarker byte = 0x1e
+
+// DefaultMaxMessageSize is the default maximum size of the payload of a frame
+// in bytes. [ReadFrame] rejects the frames that claim to be larger without
+// reading or allocating the payload, and the handler returned by
+// [NewFramedHandler] does not write them. The limit can be changed with
+// [ReadFrameLimit] and FrameOptions.MaxMessageSize.
+const DefaultMaxMessageSize = 16 << 20
+
+// frameHeaderSize is the size of the frame header: the marker and the length
+// of the payload as a big-endian uint32.
+const frameHeaderSize = 5
+
+// Errors returned by the framed logging.
+var (
+	errFrameMarker = errors.New("logs: frame does not start with the frame marker")
+	errFrameSize   = errors.New("logs: frame is too large")
+)
+
+// FrameOptions are the options for the handler returned by
+// [NewFramedHandler]. A zero FrameOptions consists entirely of the default
+// values.
+type FrameOptions struct {
+	HandlerOptions
+
+	// MaxMessageSize is the maximum size of the payload of a frame in bytes.
+	// The records that are larger are not written and the handler returns
+	// an error for them. If MaxMessageSize is zero or negative,
+	// [DefaultMaxMessageSize] is used. The reader should use the same limit
+	// with [ReadFrameLimit].
+	MaxMessageSize int
+}
+
+// frameWriter is an io.Writer that writes every call to Write as a single
+// frame.
+type frameWriter struct {
+	w       io.Writer
+	maxSize int
+}
+
+// NewFramedHandler returns a new [Handler] that writes every record to w as
+// a length-prefixed frame. It is meant for writing the logs to the standard
+// error of the plugin so that Reginald can separate them from the other output
+// of the process, for example, before the plugin can use the main protocol
+// channel. Each frame consists of [FrameMarker], the length of the payload as
+// a big-endian uint32, and the payload that is the record as a JSON object in
+// the same format as with [NewHandler] but without the trailing newline. Every
+// frame is written with a single call to w.Write. Use [ReadFrame] to read
+// the frames. If opts is nil, the default options are used.
+func NewFramedHandler(w io.Writer, opts *FrameOptions) *Handler {
+	var o FrameOptions
+
+	if opts != nil {
+		o = *opts
+	}
+
+	fw := &frameWriter{w: w, maxSize: maxMessageSize(o.MaxMessageSize)}
+
+	return NewHandler(fw, &o.HandlerOptions)
+}
+
+// ReadFrame reads a single frame written by the handler returned by
+// [NewFramedHandler] from r and returns its payload. It returns [io.EOF] if r
+// has no more data at the start of a frame and an error if the frame is
+// malformed or truncated. If the length of the payload is greater than
+// [DefaultMaxMessageSize], it returns an error after reading only the header.
+// As the payload is then left unread, the stream can no longer be read in sync
+// and the reader should be closed.
+func ReadFrame(r io.Reader) ([]byte, error) {
+	return ReadFrameLimit(r, DefaultMaxMessageSize)
+}
+
+// ReadFrameLimit reads a single frame from r as [ReadFrame] does, but it
+// rejects the frames whose payload is larger than maxSize bytes instead of
+// [DefaultMaxMessageSize]. If maxSize is zero or negative,
+// DefaultMaxMessageSize is used.
+func ReadFrameLimit(r io.Reader, maxSize int) ([]byte, error) {
+	maxSize = maxMessageSize(maxSize)
+
+	var header [frameHeaderSize]byte
+
+	if _, err := io.ReadFull(r, header[:]); err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, io.EOF
+		}
+
+		return nil, fmt.Errorf("logs: failed to read frame header: %w", err)
+	}
+
+	if header[0] != FrameMarker {
+		return nil, fmt.Errorf("%w: got %#x", errFrameMarker, header[0])
+	}
+
+	n := binary.BigEndian.Uint32(header[1:])
+	if uint64(n) > uint64(maxSize) {
+		return nil, fmt.Errorf("%w: %d bytes, the limit is %d", errFrameSize, n, maxSize)
+	}
+
+	payload := make([]byte, n)
+
+	if _, err := io.ReadFull(r, payload); err != nil {
+		return nil, fmt.Errorf("logs: failed to read frame payload: %w", err)
+	}
+
+	return payload, nil
+}
+
+// Write writes p without its trailing newline as a single frame.
+func (fw *frameWriter) Write(p []byte) (int, error) {
+	payload := bytes.TrimSuffix(p, []byte{'\n'})
+	if len(payload) > fw.maxSize || uint64(len(payload)) > math.MaxUint32 {
+		return 0, fmt.Errorf(
+			"%w: %d bytes, the limit is %d",
+			errFrameSize,
+			len(payload),
+			fw.maxSize,
+		)
+	}
+
+	frame := make([]byte, frameHeaderSize, frameHeaderSize+len(payload))
+	frame[0] = FrameMarker
+	binary.BigEndian.PutUint32(frame[1:], uint32(len(payload))) //nolint:gosec // checked above
+	frame = append(frame, payload...)
+
+	if _, err := fw.w.Write(frame); err != nil {
+		return 0, fmt.Errorf("%w", err)
+	}
+
+	return len(p), nil
+}
+
+// maxMessageSize returns size or [DefaultMaxMessageSize] if size is zero or
+// negative.
+func maxMessageSize(size int) int {
+	if size <= 0 {
+		return DefaultMaxMessageSize
+	}
+
+	return size
+}
@@ -0,0 +1,229 @@
+// Copyright 2025 Antti Kivi
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logs_test
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/reginald-project/reginald-sdk-go/logs"
+)
+
+func TestFramedHandler(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+
+	logger := slog.New(logs.NewFramedHandler(
+		&buf,
+		&logs.FrameOptions{HandlerOptions: logs.HandlerOptions{Level: logs.LevelTrace}},
+	))
+	logger.Log(t.Context(), logs.LevelTrace.Level(), "starting", "n", 1)
+	logger.Warn("line\nbreak")
+
+	for _, want := range []struct {
+		level logs.Level
+		msg   string
+	}{
+		{logs.LevelTrace, "starting"},
+		{logs.LevelWarn, "line\nbreak"},
+	} {
+		payload, err := logs.ReadFrame(&buf)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var rec struct {
+			Level logs.Level `json:"level"`
+			Msg   string     `json:"msg"`
+		}
+
+		if err := json.Unmarshal(payload, &rec); err != nil {
+			t.Fatalf("invalid payload %q: %v", payload, err)
+		}
+
+		if rec.Level != want.level || rec.Msg != want.msg {
+			t.Errorf("got %s %q, want %s %q", rec.Level, rec.Msg, want.level, want.msg)
+		}
+	}
+
+	if _, err := logs.ReadFrame(&buf); !errors.Is(err, io.EOF) {
+		t.Errorf("got %v, want EOF", err)
+	}
+}
+
+func TestReadFrameError(t *testing.T) {
+	t.Parallel()
+
+	for _, data := range [][]byte{
+		[]byte("{\"msg\":\"raw output\"}\n"),
+		{logs.FrameMarker, 0, 0},
+		{logs.FrameMarker, 0, 0, 0, 4, '{', '}'},
+	} {
+		if _, err := logs.ReadFrame(bytes.NewReader(data)); err == nil || errors.Is(err, io.EOF) {
+			t.Errorf("%q: got %v, want frame error", data, err)
+		}
+	}
+}
+
+func TestReadFrameMaxSize(t *testing.T) {
+	t.Parallel()
+
+	payload, err := logs.ReadFrame(
+		bytes.NewReader(
+			frame(logs.DefaultMaxMessageSize, make([]byte, logs.DefaultMaxMessageSize)),
+		),
+	)
+	if err != nil || len(payload) != logs.DefaultMaxMessageSize {
+		t.Errorf(
+			"frame at the limit: got %d bytes, %v, want %d bytes",
+			len(payload),
+			err,
+			logs.DefaultMaxMessageSize,
+		)
+	}
+
+	_, err = logs.ReadFrame(bytes.NewReader(frame(logs.DefaultMaxMessageSize+1, nil)))
+	if err == nil || !strings.Contains(err.Error(), "frame is too large") {
+		t.Errorf(
+			"frame over the limit: got %v, want string containing %q",
+			err,
+			"frame is too large",
+		)
+	}
+
+	var buf bytes.Buffer
+
+	h := logs.NewFramedHandler(&buf, nil)
+	r := slog.NewRecord(
+		testTime,
+		slog.LevelInfo,
+		strings.Repeat("x", logs.DefaultMaxMessageSize),
+		0,
+	)
+
+	if err := h.Handle(t.Context(), r); err == nil || buf.Len() != 0 {
+		t.Errorf("got %v and %d bytes written, want frame size error", err, buf.Len())
+	}
+}
+
+func TestReadFrameLimit(t *testing.T) {
+	t.Parallel()
+
+	const limit = 64
+
+	tests := []struct {
+		name    string
+		size    int
+		wantErr bool
+	}{
+		{"UnderLimit", limit - 1, false},
+		{"AtLimit", limit, false},
+		{"OverLimit", limit + 1, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			data := frame(uint32(tt.size), make([]byte, tt.size)) //nolint:gosec // small test sizes
+
+			payload, err := logs.ReadFrameLimit(bytes.NewReader(data), limit)
+			if tt.wantErr {
+				if err == nil || !strings.Contains(err.Error(), "frame is too large") {
+					t.Errorf("got %v, want string containing %q", err, "frame is too large")
+				}
+
+				return
+			}
+
+			if err != nil || len(payload) != tt.size {
+				t.Errorf("got %d bytes, %v, want %d bytes", len(payload), err, tt.size)
+			}
+		})
+	}
+}
+
+func TestFramedHandlerMaxMessageSize(t *testing.T) {
+	t.Parallel()
+
+	const limit = 256
+
+	//nolint:exhaustruct // default handler options
+	opts := &logs.FrameOptions{MaxMessageSize: limit}
+
+	// The payload is the JSON object of the record, so measure the overhead
+	// of an empty message first and fill the rest up to the limit.
+	var probe bytes.Buffer
+
+	empty := slog.NewRecord(testTime, slog.LevelInfo, "", 0)
+	if err := logs.NewFramedHandler(&probe, opts).Handle(t.Context(), empty); err != nil {
+		t.Fatal(err)
+	}
+
+	overhead := probe.Len() - 5
+
+	tests := []struct {
+		name    string
+		size    int
+		wantErr bool
+	}{
+		{"UnderLimit", limit - 1, false},
+		{"AtLimit", limit, false},
+		{"OverLimit", limit + 1, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var buf bytes.Buffer
+
+			h := logs.NewFramedHandler(&buf, opts)
+			r := slog.NewRecord(testTime, slog.LevelInfo, strings.Repeat("x", tt.size-overhead), 0)
+
+			err := h.Handle(t.Context(), r)
+			if tt.wantErr {
+				if err == nil || buf.Len() != 0 {
+					t.Errorf("got %v and %d bytes written, want frame size error", err, buf.Len())
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			payload, err := logs.ReadFrameLimit(&buf, limit)
+			if err != nil || len(payload) != tt.size {
+				t.Errorf("got %d bytes, %v, want %d bytes", len(payload), err, tt.size)
+			}
+		})
+	}
+}
+
+func frame(n uint32, payload []byte) []byte {
+	header := []byte{logs.FrameMarker, 0, 0, 0, 0}
+	binary.BigEndian.PutUint32(header[1:], n)
+
+	return append(header, payload...)
+}
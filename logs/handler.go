@@ -0,0 +1,455 @@
+// Copyright 2025 Antti Kivi
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logs
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"runtime"
+	"slices"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// RedactedValue is the value that replaces the values of the redacted
+// attributes. See HandlerOptions.RedactKeys.
+const RedactedValue = "***"
+
+// Keys for the standard fields of the records written by Handler.
+const (
+	TimeKey    = "time"
+	LevelKey   = "level"
+	MessageKey = "msg"
+	SourceKey  = "source"
+	FieldsKey  = "fields"
+)
+
+// HandlerOptions are the options for a Handler. A zero HandlerOptions
+// consists entirely of the default values.
+type HandlerOptions struct {
+	// Level reports the minimum level of the records that are logged.
+	// The handler discards records with lower levels. If Level is nil,
+	// the handler assumes [LevelInfo].
+	Level slog.Leveler
+
+	// AddSource causes the handler to add the source code position of the log
+	// statement to the output as "source". It is false by default as resolving
+	// the position has a cost.
+	AddSource bool
+
+	// RedactKeys are the keys of the attributes whose values are replaced
+	// with [RedactedValue] in the output, for example, the keys returned by
+	// api.Manifest.SensitiveKeys. The keys are matched against the attributes
+	// at every level, including the attributes within groups.
+	RedactKeys []string
+
+	// ContextKeys are the context keys whose values the handler adds to every
+	// record that is logged with a context that has them, for example,
+	// [RunIDContextKey]. The values are added as top-level attributes keyed
+	// by the string value of the key, before the attributes added with
+	// WithAttrs and WithGroup. The keys that the context has no value for are
+	// omitted.
+	ContextKeys []ContextKey
+}
+
+// Handler is a [slog.Handler] that writes the records to an [io.Writer] as
+// newline-delimited JSON. Every record is written as a single JSON object on
+// its own line and the fields of the object are always in the same order:
+//
+//   - "time" is the time of the record formatted as RFC 3339 with
+//     nanoseconds. It is omitted if the time of the record is zero.
+//   - "level" is the level of the record formatted using [Level.String], for
+//     example "TRACE" or "INFO+2".
+//   - "msg" is the log message.
+//   - "source" is the source code position of the log statement as an object
+//     with "function", "file", and "line". It is only written if
+//     HandlerOptions.AddSource is set and the record has the position.
+//
+// The attributes of the record follow the standard fields in the order they
+// were added, first the ones added with [Handler.WithAttrs] and then the ones
+// in the record itself. The values of HandlerOptions.ContextKeys found in
+// the context of the record precede them. Groups are written as nested JSON
+// objects. If the Handler is created with [NewJSONHandler], the attributes are
+// written under "fields" instead of next to the standard fields.
+type Handler struct {
+	opts   HandlerOptions
+	goas   []groupOrAttrs
+	mu     *sync.Mutex
+	w      io.Writer
+	fields bool
+	redact map[string]bool
+}
+
+// groupOrAttrs holds either a group name or a list of attributes added to
+// a Handler with WithGroup or WithAttrs.
+type groupOrAttrs struct {
+	group string
+	attrs []slog.Attr
+}
+
+// NewHandler returns a new [Handler] that writes to w using the given options.
+// If opts is nil, the default options are used.
+func NewHandler(w io.Writer, opts *HandlerOptions) *Handler {
+	//nolint:exhaustruct // the rest are set below
+	h := &Handler{mu: &sync.Mutex{}, w: w, fields: false}
+
+	if opts != nil {
+		h.opts = *opts
+	}
+
+	if h.opts.Level == nil {
+		h.opts.Level = LevelInfo
+	}
+
+	if len(h.opts.RedactKeys) > 0 {
+		h.redact = make(map[string]bool, len(h.opts.RedactKeys))
+
+		for _, k := range h.opts.RedactKeys {
+			h.redact[k] = true
+		}
+	}
+
+	return h
+}
+
+// NewJSONHandler returns a new [Handler] that writes to w using the log schema
+// that Reginald ingests. The records have the same standard fields as with
+// [NewHandler] but the attributes, including the groups, are nested under
+// the "fields" object. The "fields" object is omitted if the record has no
+// attributes. If opts is nil, the default options are used.
+func NewJSONHandler(w io.Writer, opts *HandlerOptions) *Handler {
+	h := NewHandler(w, opts)
+	h.fields = true
+
+	return h
+}
+
+// Enabled reports whether the handler handles records at the given level.
+func (h *Handler) Enabled(_ context.Context, level slog.Level) bool {
+	return level >= h.opts.Level.Level()
+}
+
+// Handle formats the record as a single line of JSON and writes it.
+//
+//nolint:gocritic // implements interface
+func (h *Handler) Handle(ctx context.Context, r slog.Record) error {
+	buf := make([]byte, 0, 1024) //nolint:mnd // initial size is arbitrary
+	buf = append(buf, '{')
+
+	if !r.Time.IsZero() {
+		buf = appendKey(buf, TimeKey)
+		buf = append(buf, '"')
+		buf = r.Time.AppendFormat(buf, time.RFC3339Nano)
+		buf = append(buf, '"', ',')
+	}
+
+	buf = appendKey(buf, LevelKey)
+	buf = append(buf, '"')
+	buf, _ = Level(r.Level).AppendText(buf) //nolint:errcheck // never fails
+	buf = append(buf, '"')
+	buf = append(buf, ',')
+	buf = appendKey(buf, MessageKey)
+	buf = appendString(buf, r.Message)
+
+	if h.opts.AddSource && r.PC != 0 {
+		buf = appendSource(buf, r.PC)
+	}
+
+	envelope := len(buf)
+
+	if h.fields {
+		buf = append(buf, ',')
+		buf = appendKey(buf, FieldsKey)
+		buf = append(buf, '{')
+	}
+
+	attrStart := len(buf)
+
+	for _, key := range h.opts.ContextKeys {
+		if v := ctx.Value(key); v != nil {
+			buf = appendAttrs(
+				buf,
+				[]slog.Attr{h.redactAttr(slog.Any(string(key), v))},
+				atObjectStart(buf),
+			)
+		}
+	}
+
+	goas := h.goas
+
+	// Groups that would be left empty are omitted.
+	if r.NumAttrs() == 0 {
+		for len(goas) > 0 && goas[len(goas)-1].group != "" {
+			goas = goas[:len(goas)-1]
+		}
+	}
+
+	groups := 0
+
+	for _, goa := range goas {
+		if goa.group != "" {
+			if !atObjectStart(buf) {
+				buf = append(buf, ',')
+			}
+
+			buf = appendKey(buf, goa.group)
+			buf = append(buf, '{')
+			groups++
+
+			continue
+		}
+
+		buf = appendAttrs(buf, goa.attrs, atObjectStart(buf))
+	}
+
+	attrs := make([]slog.Attr, 0, r.NumAttrs())
+
+	r.Attrs(func(a slog.Attr) bool {
+		attrs = append(attrs, h.redactAttr(a))
+
+		return true
+	})
+
+	buf = appendAttrs(buf, attrs, atObjectStart(buf))
+
+	for range groups {
+		buf = append(buf, '}')
+	}
+
+	if h.fields {
+		if len(buf) == attrStart {
+			buf = buf[:envelope]
+		} else {
+			buf = append(buf, '}')
+		}
+	}
+
+	buf = append(buf, '}', '\n')
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if _, err := h.w.Write(buf); err != nil {
+		return fmt.Errorf("failed to write log record: %w", err)
+	}
+
+	return nil
+}
+
+// WithAttrs returns a new [Handler] whose output has the given attributes
+// added after the attributes of h.
+func (h *Handler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	if len(attrs) == 0 {
+		return h
+	}
+
+	if h.redact != nil {
+		redacted := make([]slog.Attr, len(attrs))
+
+		for i, a := range attrs {
+			redacted[i] = h.redactAttr(a)
+		}
+
+		attrs = redacted
+	}
+
+	return h.withGroupOrAttrs(groupOrAttrs{group: "", attrs: attrs})
+}
+
+// WithGroup returns a new [Handler] that nests the attributes added after it
+// under the given group name.
+func (h *Handler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+
+	return h.withGroupOrAttrs(groupOrAttrs{group: name, attrs: nil})
+}
+
+// redactAttr returns a with its value replaced with RedactedValue if its key is
+// redacted. The attributes within groups are redacted recursively.
+func (h *Handler) redactAttr(a slog.Attr) slog.Attr {
+	if h.redact == nil {
+		return a
+	}
+
+	if h.redact[a.Key] {
+		return slog.String(a.Key, RedactedValue)
+	}
+
+	a.Value = a.Value.Resolve()
+
+	if a.Value.Kind() != slog.KindGroup {
+		return a
+	}
+
+	group := a.Value.Group()
+	attrs := make([]slog.Attr, len(group))
+
+	for i, ga := range group {
+		attrs[i] = h.redactAttr(ga)
+	}
+
+	return slog.Attr{Key: a.Key, Value: slog.GroupValue(attrs...)}
+}
+
+func (h *Handler) withGroupOrAttrs(goa groupOrAttrs) *Handler {
+	h2 := *h
+	h2.goas = append(slices.Clip(h.goas), goa)
+
+	return &h2
+}
+
+// appendAttrs appends the attributes to buf. If first is true, the first
+// attribute is not preceded by a comma.
+func appendAttrs(buf []byte, attrs []slog.Attr, first bool) []byte {
+	for _, a := range attrs {
+		var ok bool
+
+		buf, ok = appendAttr(buf, a, first)
+		if ok {
+			first = false
+		}
+	}
+
+	return buf
+}
+
+// appendAttr appends a single attribute to buf and reports whether anything
+// was appended. Attributes with empty keys and empty groups are omitted, and
+// groups with empty keys are inlined.
+func appendAttr(buf []byte, a slog.Attr, first bool) ([]byte, bool) {
+	a.Value = a.Value.Resolve()
+
+	if a.Value.Kind() == slog.KindGroup {
+		attrs := a.Value.Group()
+		if len(attrs) == 0 {
+			return buf, false
+		}
+
+		if a.Key == "" {
+			n := len(buf)
+			buf = appendAttrs(buf, attrs, first)
+
+			return buf, len(buf) > n
+		}
+
+		if !first {
+			buf = append(buf, ',')
+		}
+
+		buf = appendKey(buf, a.Key)
+		buf = append(buf, '{')
+		buf = appendAttrs(buf, attrs, true)
+		buf = append(buf, '}')
+
+		return buf, true
+	}
+
+	if a.Key == "" {
+		return buf, false
+	}
+
+	if !first {
+		buf = append(buf, ',')
+	}
+
+	buf = appendKey(buf, a.Key)
+	buf = appendValue(buf, a.Value)
+
+	return buf, true
+}
+
+// appendSource appends the source code position of pc to buf as a "source"
+// field, preceded by a comma.
+func appendSource(buf []byte, pc uintptr) []byte {
+	frame, _ := runtime.CallersFrames([]uintptr{pc}).Next()
+
+	buf = append(buf, ',')
+	buf = appendKey(buf, SourceKey)
+	buf = append(buf, '{')
+	buf = appendKey(buf, "function")
+	buf = appendString(buf, frame.Function)
+	buf = append(buf, ',')
+	buf = appendKey(buf, "file")
+	buf = appendString(buf, frame.File)
+	buf = append(buf, ',')
+	buf = appendKey(buf, "line")
+	buf = strconv.AppendInt(buf, int64(frame.Line), 10)
+
+	return append(buf, '}')
+}
+
+// atObjectStart reports whether buf ends at the start of a JSON object so that
+// the next field must not be preceded by a comma.
+func atObjectStart(buf []byte) bool {
+	return len(buf) > 0 && buf[len(buf)-1] == '{'
+}
+
+func appendKey(buf []byte, key string) []byte {
+	buf = appendString(buf, key)
+
+	return append(buf, ':')
+}
+
+func appendValue(buf []byte, v slog.Value) []byte {
+	switch v.Kind() {
+	case slog.KindString:
+		return appendString(buf, v.String())
+	case slog.KindInt64:
+		return strconv.AppendInt(buf, v.Int64(), 10)
+	case slog.KindUint64:
+		return strconv.AppendUint(buf, v.Uint64(), 10)
+	case slog.KindFloat64:
+		return appendJSON(buf, v.Float64())
+	case slog.KindBool:
+		return strconv.AppendBool(buf, v.Bool())
+	case slog.KindDuration:
+		return strconv.AppendInt(buf, int64(v.Duration()), 10)
+	case slog.KindTime:
+		buf = append(buf, '"')
+		buf = v.Time().AppendFormat(buf, time.RFC3339Nano)
+
+		return append(buf, '"')
+	case slog.KindAny, slog.KindGroup, slog.KindLogValuer:
+		fallthrough
+	default:
+		if err, ok := v.Any().(error); ok {
+			return appendString(buf, err.Error())
+		}
+
+		return appendJSON(buf, v.Any())
+	}
+}
+
+func appendString(buf []byte, s string) []byte {
+	return appendJSON(buf, s)
+}
+
+// appendJSON appends the JSON encoding of v to buf. If v cannot be encoded,
+// its default string representation is appended as a JSON string instead.
+func appendJSON(buf []byte, v any) []byte {
+	data, err := json.Marshal(v)
+	if err != nil {
+		//nolint:errchkjson // strings are always encoded
+		data, _ = json.Marshal(fmt.Sprintf("%+v", v))
+	}
+
+	return append(buf, data...)
+}
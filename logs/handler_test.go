@@ -0,0 +1,363 @@
+// Copyright 2025 Antti Kivi
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logs_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"slices"
+	"strings"
+	"testing"
+	"testing/slogtest"
+	"time"
+
+	"github.com/reginald-project/reginald-sdk-go/logs"
+)
+
+var testTime = time.Date(2025, time.June, 1, 12, 30, 0, 0, time.UTC)
+
+func TestHandlerKeyOrder(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+
+	h := logs.NewHandler(&buf, &logs.HandlerOptions{Level: logs.LevelTrace})
+	h2 := h.WithAttrs([]slog.Attr{slog.String("z", "first")})
+
+	r := slog.NewRecord(testTime, logs.LevelTrace.Level(), "hello", 0)
+	r.AddAttrs(slog.Int("b", 1), slog.Int("a", 2))
+
+	if err := h2.Handle(context.Background(), r); err != nil {
+		t.Fatal(err)
+	}
+
+	want := `{"time":"2025-06-01T12:30:00Z","level":"TRACE","msg":"hello",` +
+		`"z":"first","b":1,"a":2}` + "\n"
+	if got := buf.String(); got != want {
+		t.Errorf("got %s, want %s", got, want)
+	}
+
+	wantKeys := []string{"time", "level", "msg", "z", "b", "a"}
+	if got := keys(t, buf.Bytes()); !slices.Equal(got, wantKeys) {
+		t.Errorf("got keys %v, want %v", got, wantKeys)
+	}
+}
+
+func TestHandlerLevel(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+
+	logger := slog.New(logs.NewHandler(&buf, nil))
+
+	logger.Debug("hidden")
+	logger.Log(context.Background(), logs.LevelInfo.Level()+1, "shown")
+
+	var got map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+		t.Fatal(err)
+	}
+
+	if got["level"] != "INFO+1" || got["msg"] != "shown" {
+		t.Errorf("got %v, want level INFO+1 and message shown", got)
+	}
+}
+
+// keys returns the top-level keys of the JSON object in data in the order
+// they appear in.
+func keys(t *testing.T, data []byte) []string {
+	t.Helper()
+
+	dec := json.NewDecoder(bytes.NewReader(data))
+
+	if _, err := dec.Token(); err != nil {
+		t.Fatal(err)
+	}
+
+	var result []string
+
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		key, ok := tok.(string)
+		if !ok {
+			t.Fatalf("unexpected token %v", tok)
+		}
+
+		result = append(result, key)
+
+		var v json.RawMessage
+		if err := dec.Decode(&v); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	return result
+}
+
+func TestJSONHandler(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+
+	h := logs.NewJSONHandler(&buf, &logs.HandlerOptions{Level: logs.LevelTrace})
+	h2 := h.WithAttrs([]slog.Attr{slog.String("plugin", "example")}).WithGroup("task")
+
+	r := slog.NewRecord(testTime, (logs.LevelTrace + 1).Level(), "running", 0)
+	r.AddAttrs(slog.String("type", "link"), slog.Int("count", 3))
+
+	if err := h2.Handle(context.Background(), r); err != nil {
+		t.Fatal(err)
+	}
+
+	r = slog.NewRecord(time.Time{}, slog.LevelInfo, "done", 0)
+
+	if err := h2.Handle(context.Background(), r); err != nil {
+		t.Fatal(err)
+	}
+
+	want := `{"time":"2025-06-01T12:30:00Z","level":"TRACE+1","msg":"running",` +
+		`"fields":{"plugin":"example","task":{"type":"link","count":3}}}` + "\n" +
+		`{"level":"INFO","msg":"done","fields":{"plugin":"example"}}` + "\n"
+	if got := buf.String(); got != want {
+		t.Errorf("got %s, want %s", got, want)
+	}
+
+	buf.Reset()
+
+	if err := h.Handle(context.Background(), r); err != nil {
+		t.Fatal(err)
+	}
+
+	if got, want := buf.String(), `{"level":"INFO","msg":"done"}`+"\n"; got != want {
+		t.Errorf("got %s, want %s", got, want)
+	}
+
+	buf.Reset()
+
+	r.AddAttrs(slog.Bool("ok", true))
+
+	if err := h.WithGroup("g").Handle(context.Background(), r); err != nil {
+		t.Fatal(err)
+	}
+
+	want = `{"level":"INFO","msg":"done","fields":{"g":{"ok":true}}}` + "\n"
+	if got := buf.String(); got != want {
+		t.Errorf("got %s, want %s", got, want)
+	}
+}
+
+func TestHandlerAddSource(t *testing.T) {
+	t.Parallel()
+
+	for _, addSource := range []bool{true, false} {
+		var buf bytes.Buffer
+
+		slog.New(
+			logs.NewJSONHandler(&buf, &logs.HandlerOptions{AddSource: addSource}),
+		).Info("hello", "a", 1)
+
+		var rec struct {
+			Source *struct {
+				Function string `json:"function"`
+				File     string `json:"file"`
+				Line     int    `json:"line"`
+			} `json:"source"`
+		}
+
+		if err := json.Unmarshal(buf.Bytes(), &rec); err != nil {
+			t.Fatalf("invalid record %q: %v", buf.Bytes(), err)
+		}
+
+		if !addSource {
+			if rec.Source != nil {
+				t.Errorf("got source %+v, want none", *rec.Source)
+			}
+
+			continue
+		}
+
+		if rec.Source == nil {
+			t.Fatalf("got no source in %s", buf.Bytes())
+		}
+
+		if !strings.HasSuffix(rec.Source.File, "handler_test.go") || rec.Source.Line <= 0 {
+			t.Errorf(
+				"got source %s:%d, want a line in handler_test.go",
+				rec.Source.File,
+				rec.Source.Line,
+			)
+		}
+
+		if !strings.HasSuffix(rec.Source.Function, "TestHandlerAddSource") {
+			t.Errorf("got function %q, want TestHandlerAddSource", rec.Source.Function)
+		}
+	}
+}
+
+func TestHandlerRedactKeys(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+
+	h := logs.NewHandler(&buf, &logs.HandlerOptions{RedactKeys: []string{"token", "password"}})
+	logger := slog.New(h).With("token", "secret-1", "user", "alice")
+
+	logger.Info("login",
+		slog.Group("auth", slog.String("password", "secret-2"), slog.String("method", "basic")),
+		slog.Int("attempt", 1),
+	)
+
+	want := `{"level":"INFO","msg":"login","token":"***","user":"alice",` +
+		`"auth":{"password":"***","method":"basic"},"attempt":1}` + "\n"
+
+	got := buf.String()
+	if i := strings.Index(got, `"level"`); i > 0 {
+		got = "{" + got[i:]
+	}
+
+	if got != want {
+		t.Errorf("got %s, want %s", got, want)
+	}
+
+	if strings.Contains(buf.String(), "secret") {
+		t.Errorf("output contains a secret: %s", buf.String())
+	}
+}
+
+func TestHandlerSlogtest(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+
+	newHandler := func(*testing.T) slog.Handler {
+		buf.Reset()
+
+		return logs.NewHandler(&buf, nil)
+	}
+
+	result := func(t *testing.T) map[string]any {
+		t.Helper()
+
+		var m map[string]any
+
+		if err := json.Unmarshal(buf.Bytes(), &m); err != nil {
+			t.Fatalf("invalid record %q: %v", buf.Bytes(), err)
+		}
+
+		return m
+	}
+
+	slogtest.Run(t, newHandler, result)
+}
+
+func TestHandlerGroupLevel(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+
+	logger := slog.New(logs.NewHandler(&buf, &logs.HandlerOptions{Level: logs.LevelTrace}))
+	logger = logger.With("a", 1).WithGroup("g").With("b", 2).WithGroup("h")
+
+	logger.Log(
+		context.Background(),
+		logs.LevelTrace.Level(),
+		"msg",
+		"level",
+		logs.LevelTrace,
+		"min",
+		logs.LevelWarn-2,
+	)
+
+	got := buf.String()
+	if i := strings.Index(got, `"level"`); i > 0 {
+		got = "{" + got[i:]
+	}
+
+	want := `{"level":"TRACE","msg":"msg","a":1,` +
+		`"g":{"b":2,"h":{"level":"TRACE","min":"INFO+2"}}}` + "\n"
+	if got != want {
+		t.Errorf("got %s, want %s", got, want)
+	}
+}
+
+func TestHandlerContextKeys(t *testing.T) {
+	t.Parallel()
+
+	for _, test := range []struct {
+		name       string
+		newHandler func(io.Writer, *logs.HandlerOptions) *logs.Handler
+		ctx        context.Context
+		want       string
+	}{
+		{
+			"Handler",
+			logs.NewHandler,
+			logs.ContextWithRunID(t.Context(), "run-1"),
+			`{"level":"INFO","msg":"hello","runId":"run-1","g":{"a":1}}`,
+		},
+		{
+			"JSONHandler",
+			logs.NewJSONHandler,
+			logs.ContextWithRunID(t.Context(), "run-2"),
+			`{"level":"INFO","msg":"hello","fields":{"runId":"run-2","g":{"a":1}}}`,
+		},
+		{
+			"NoValue",
+			logs.NewHandler,
+			t.Context(),
+			`{"level":"INFO","msg":"hello","g":{"a":1}}`,
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			var buf bytes.Buffer
+
+			h := test.newHandler(
+				&buf,
+				&logs.HandlerOptions{ContextKeys: []logs.ContextKey{logs.RunIDContextKey}},
+			)
+			r := slog.NewRecord(time.Time{}, slog.LevelInfo, "hello", 0)
+			r.AddAttrs(slog.Int("a", 1))
+
+			if err := h.WithGroup("g").Handle(test.ctx, r); err != nil {
+				t.Fatal(err)
+			}
+
+			if got := strings.TrimSuffix(buf.String(), "\n"); got != test.want {
+				t.Errorf("got %s, want %s", got, test.want)
+			}
+		})
+	}
+}
+
+func TestWithRunID(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+
+	logs.WithRunID(slog.New(logs.NewHandler(&buf, nil)), "run-1").Info("hello")
+
+	if want := `"runId":"run-1"`; !strings.Contains(buf.String(), want) {
+		t.Errorf("got %s, want string containing %q", buf.String(), want)
+	}
+}
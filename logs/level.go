@@ -16,9 +16,12 @@
 package logs
 
 import (
+	"encoding/json"
 	"errors"
 	"fmt"
 	"log/slog"
+	"math"
+	"os"
 	"strconv"
 	"strings"
 )
@@ -34,6 +37,7 @@ const (
 
 // Errors for the log utilities.
 var (
+	errNotInteger  = errors.New("level number is not an integer")
 	errUnknownName = errors.New("level has unknown name")
 )
 
@@ -41,21 +45,155 @@ var (
 // the more important or severe the event.
 type Level slog.Level //nolint:recvcheck // TODO: Can the receivers have the same type?
 
+// Enabled reports whether a record at the given level should be logged when
+// the minimum level is min. In other words, it reports whether level is at
+// least as severe as min. As the levels more verbose than [LevelDebug] are
+// negative, for example, LevelTrace is enabled only if min is LevelTrace
+// or lower.
+func Enabled(min, level Level) bool { //nolint:predeclared // min is the clearest name
+	return level >= min
+}
+
+// LevelStringTable returns the output of [Level.String] for every level from
+// from to to, inclusive. It returns an empty map if from is greater than to.
+// It is meant for testing the names of the levels in bulk, for example, to
+// check the boundaries between the named levels.
+func LevelStringTable(from, to Level) map[Level]string {
+	table := make(map[Level]string, max(int(to-from)+1, 0))
+
+	for l := from; l <= to; l++ {
+		table[l] = l.String()
+	}
+
+	return table
+}
+
+// LevelFromEnv returns the level in the environment variable key parsed with
+// [ParseLevel]. It returns def if the variable is unset, empty, or cannot be
+// parsed. Use [LookupLevelEnv] to find out whether the value was invalid.
+func LevelFromEnv(key string, def Level) Level {
+	l, err := LookupLevelEnv(key, def)
+	if err != nil {
+		return def
+	}
+
+	return l
+}
+
+// LookupLevelEnv returns the level in the environment variable key parsed with
+// [ParseLevel]. It returns def if the variable is unset or empty. If the value
+// cannot be parsed, it returns def and an error.
+func LookupLevelEnv(key string, def Level) (Level, error) {
+	s := os.Getenv(key)
+	if s == "" {
+		return def, nil
+	}
+
+	l, err := ParseLevel(s)
+	if err != nil {
+		return def, fmt.Errorf("logs: environment variable %s: %w", key, err)
+	}
+
+	return l, nil
+}
+
+// ParseLevel parses s as a level. It accepts the same strings as
+// [Level.UnmarshalText], ignoring case and surrounding whitespace. Any other
+// text after the name of the level must be a signed offset, so, for example,
+// "INFO 2" and "INFO2" are errors.
+func ParseLevel(s string) (Level, error) {
+	var l Level
+
+	if err := l.parse(s); err != nil {
+		return 0, err
+	}
+
+	return l, nil
+}
+
+// ParseLevelStrict parses s as one of the named levels, ignoring case. Unlike
+// [ParseLevel], it does not accept offsets, surrounding whitespace, or any
+// other text, so, for example, "info" is [LevelInfo] but "INFO+2" and
+// " INFO" are errors. It is meant for the inputs, like a selection in a user
+// interface, where only the named levels are valid.
+func ParseLevelStrict(s string) (Level, error) {
+	l, ok := namedLevel(s)
+	if !ok {
+		return 0, fmt.Errorf("%w: %q", errUnknownName, s)
+	}
+
+	return l, nil
+}
+
+// ParseLevelRelative parses s as a level. If s starts with a sign and has no
+// name, like "+2" or "-4", it is an offset that is applied to base using
+// [Level.Offset]. As with the names, a positive offset makes the level more
+// severe and a negative offset makes it more verbose, so "-4" applied to
+// [LevelInfo] is [LevelDebug]. Otherwise, s is parsed the same way as in
+// [Level.UnmarshalText] and base is ignored. In particular, a name with
+// a sign, like "INFO+2", is an absolute level and not relative to base.
+// The surrounding whitespace is ignored like in ParseLevel.
+func ParseLevelRelative(s string, base Level) (Level, error) {
+	if t := strings.TrimSpace(s); t != "" && (t[0] == '+' || t[0] == '-') {
+		n, err := strconv.Atoi(t)
+		if err != nil {
+			return 0, fmt.Errorf("%w: relative level %q: %w", errNotInteger, s, err)
+		}
+
+		return base.Offset(n), nil
+	}
+
+	return ParseLevel(s)
+}
+
 // Level returns the [slog.Level] for l.
 func (l Level) Level() slog.Level {
 	return slog.Level(l)
 }
 
+// Add returns the level n steps more severe than l. It is an alias of
+// [Level.Offset] that reads as the counterpart of [Level.Sub].
+func (l Level) Add(n int) Level {
+	return l.Offset(n)
+}
+
+// Sub returns the level n steps more verbose than l. It is the same as
+// l.Offset(-n). For example, LevelWarn.Sub(3) is "INFO+1".
+func (l Level) Sub(n int) Level {
+	return l.Offset(-n)
+}
+
+// Offset returns the level n steps from l. A positive n makes the level more
+// severe and a negative n makes it more verbose. For example,
+// LevelInfo.Offset(-4) is [LevelDebug].
+func (l Level) Offset(n int) Level {
+	return l + Level(n)
+}
+
 // String returns a name for the level. If the level has a name, then that name
 // in uppercase is returned. If the level is between named values, then
-// an integer is appended to the uppercased name.
+// an integer is appended to the uppercased name. The names of the exact named
+// levels are returned without allocating.
 func (l Level) String() string {
+	switch l {
+	case LevelTrace:
+		return "TRACE"
+	case LevelDebug:
+		return "DEBUG"
+	case LevelInfo:
+		return "INFO"
+	case LevelWarn:
+		return "WARN"
+	case LevelError:
+		return "ERROR"
+	}
+
 	str := func(base string, val Level) string {
-		if val == 0 {
-			return base
+		if val > 0 {
+			return base + "+" + strconv.Itoa(int(val))
 		}
 
-		return fmt.Sprintf("%s%+d", base, val)
+		return base + strconv.Itoa(int(val))
 	}
 
 	switch {
@@ -81,8 +219,32 @@ func (l Level) MarshalJSON() ([]byte, error) { //nolint:unparam // implements in
 // UnmarshalJSON implements [encoding/json.Unmarshaler] It accepts any string
 // produced by [Level.MarshalJSON], ignoring case. It also accepts numeric
 // offsets that would result in a different string on output. For example,
-// "Error-8" would marshal as "INFO".
+// "Error-8" would marshal as "INFO". In addition to strings, it accepts bare
+// JSON numbers that are used as the numeric value of the level as is, so -4
+// is "DEBUG" and 0 is "INFO". The number must be a whole number but it may be
+// written in any JSON form, like -4.0 or 1e1. As with the other types, null
+// leaves the level unchanged.
 func (l *Level) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		return nil
+	}
+
+	if len(data) > 0 && data[0] != '"' {
+		var n json.Number
+		if err := json.Unmarshal(data, &n); err != nil {
+			return fmt.Errorf("logs: level number %s: %w", data, err)
+		}
+
+		level, err := numberLevel(n)
+		if err != nil {
+			return err
+		}
+
+		*l = level
+
+		return nil
+	}
+
 	s, err := strconv.Unquote(string(data))
 	if err != nil {
 		return fmt.Errorf("%w", err)
@@ -109,37 +271,81 @@ func (l *Level) UnmarshalText(data []byte) error {
 	return l.parse(string(data))
 }
 
+// parse parses s as a level name with an optional signed offset. Surrounding
+// whitespace is ignored. The name must consist of ASCII letters only and
+// anything after it must be a valid offset, so internal whitespace or other
+// characters after the name or the offset are syntax errors.
 func (l *Level) parse(s string) error {
-	name := s
+	s = strings.TrimSpace(s)
+	name, rest := s, ""
+
+	if i := strings.IndexFunc(
+		s,
+		func(r rune) bool { return (r < 'a' || r > 'z') && (r < 'A' || r > 'Z') },
+	); i >= 0 {
+		name, rest = s[:i], s[i:]
+	}
+
 	offset := 0
 
-	if i := strings.IndexAny(s, "+-"); i >= 0 {
-		name = s[:i]
+	if rest != "" {
+		if rest[0] != '+' && rest[0] != '-' {
+			return fmt.Errorf("logs: level string %q: %w", s, strconv.ErrSyntax)
+		}
 
 		var err error
 
-		offset, err = strconv.Atoi(s[i:])
+		offset, err = strconv.Atoi(rest)
 		if err != nil {
 			return fmt.Errorf("logs: level string %q: %w", s, err)
 		}
 	}
 
+	level, ok := namedLevel(name)
+	if !ok {
+		return fmt.Errorf("%w: %s", errUnknownName, name)
+	}
+
+	*l = level + Level(offset)
+
+	return nil
+}
+
+// numberLevel returns the level with the numeric value n. It returns an error
+// if n is not a whole number that fits in a Level.
+func numberLevel(n json.Number) (Level, error) {
+	if i, err := strconv.Atoi(n.String()); err == nil {
+		if i < math.MinInt32 || i > math.MaxInt32 {
+			return 0, fmt.Errorf("%w: %s", errNotInteger, n)
+		}
+
+		return Level(i), nil
+	}
+
+	f, err := n.Float64()
+	if err != nil || f != math.Trunc(f) || f < math.MinInt32 || f > math.MaxInt32 {
+		return 0, fmt.Errorf("%w: %s", errNotInteger, n)
+	}
+
+	return Level(f), nil
+}
+
+// namedLevel returns the level with the given name, ignoring case, and reports
+// whether the name is the name of a level. It is the table of names shared by
+// the parsers.
+func namedLevel(name string) (Level, bool) {
 	switch strings.ToUpper(name) {
 	case "TRACE":
-		*l = LevelTrace
+		return LevelTrace, true
 	case "DEBUG":
-		*l = LevelDebug
+		return LevelDebug, true
 	case "INFO":
-		*l = LevelInfo
+		return LevelInfo, true
 	case "WARN":
-		*l = LevelWarn
+		return LevelWarn, true
 	case "ERROR":
-		*l = LevelError
+		return LevelError, true
 	default:
-		return fmt.Errorf("%w: %s", errUnknownName, name)
+		return 0, false
 	}
-
-	*l += Level(offset)
-
-	return nil
 }
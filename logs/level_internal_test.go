@@ -16,6 +16,8 @@ package logs
 
 import (
 	"bytes"
+	"errors"
+	"strconv"
 	"strings"
 	"testing"
 )
@@ -50,6 +52,56 @@ func TestLevelString(t *testing.T) {
 	}
 }
 
+//nolint:paralleltest // AllocsPerRun cannot run in parallel
+func TestLevelStringAllocs(t *testing.T) {
+	for _, l := range []Level{LevelTrace, LevelDebug, LevelInfo, LevelWarn, LevelError} {
+		if n := testing.AllocsPerRun(100, func() { _ = l.String() }); n != 0 {
+			t.Errorf("%s: got %v allocations, want 0", l, n)
+		}
+	}
+}
+
+func BenchmarkLevelString(b *testing.B) {
+	b.Run("named", func(b *testing.B) {
+		b.ReportAllocs()
+
+		for b.Loop() {
+			_ = LevelWarn.String()
+		}
+	})
+
+	b.Run("offset", func(b *testing.B) {
+		b.ReportAllocs()
+
+		for b.Loop() {
+			_ = LevelInfo.Add(2).String()
+		}
+	})
+}
+
+func TestEnabled(t *testing.T) {
+	t.Parallel()
+
+	for _, test := range []struct {
+		min, level Level
+		want       bool
+	}{
+		{LevelInfo, LevelInfo, true},
+		{LevelInfo, LevelError, true},
+		{LevelInfo, LevelDebug, false},
+		{LevelInfo, LevelTrace, false},
+		{LevelDebug, LevelTrace, false},
+		{LevelTrace, LevelTrace, true},
+		{LevelTrace, LevelDebug, true},
+		{LevelTrace, LevelTrace - 1, false},
+		{LevelWarn, LevelWarn - 1, false},
+	} {
+		if got := Enabled(test.min, test.level); got != test.want {
+			t.Errorf("Enabled(%s, %s): got %t, want %t", test.min, test.level, got, test.want)
+		}
+	}
+}
+
 func TestLevelMarshalJSON(t *testing.T) {
 	t.Parallel()
 
@@ -75,6 +127,54 @@ func TestLevelMarshalJSON(t *testing.T) {
 	}
 }
 
+func TestLevelUnmarshalJSON(t *testing.T) {
+	t.Parallel()
+
+	for _, test := range []struct {
+		in   string
+		want Level
+	}{
+		{`"INFO"`, LevelInfo},
+		{`"INFO+1"`, LevelInfo + 1},
+		{`0`, LevelInfo},
+		{`-8`, LevelTrace},
+		{`-4`, LevelDebug},
+		{`9`, LevelError + 1},
+		{`-4.0`, LevelDebug},
+		{`1e1`, LevelError + 2},
+	} {
+		var got Level
+		if err := got.UnmarshalJSON([]byte(test.in)); err != nil {
+			t.Fatalf("%s: %v", test.in, err)
+		}
+
+		if got != test.want {
+			t.Errorf("%s: got %s, want %s", test.in, got, test.want)
+		}
+	}
+
+	l := LevelWarn
+	if err := l.UnmarshalJSON([]byte(`null`)); err != nil || l != LevelWarn {
+		t.Errorf("null: got %s, %v, want %s", l, err, LevelWarn)
+	}
+
+	for _, in := range []string{
+		`1.5`,
+		`-4.5`,
+		`1e100`,
+		`99999999999`,
+		`-99999999999`,
+		`true`,
+		`"INFO`,
+		`"dbg"`,
+	} {
+		var l Level
+		if err := l.UnmarshalJSON([]byte(in)); err == nil {
+			t.Errorf("%s: expected an error", in)
+		}
+	}
+}
+
 func TestLevelMarshalText(t *testing.T) {
 	t.Parallel()
 
@@ -133,6 +233,11 @@ func TestLevelParse(t *testing.T) {
 		{"INFO+87", LevelInfo + 87},
 		{"Error-18", LevelError - 18},
 		{"Error-8", LevelInfo},
+		{"INFO ", LevelInfo},
+		{" INFO", LevelInfo},
+		{" INFO ", LevelInfo},
+		{"INFO+2 ", LevelInfo + 2},
+		{"\tdebug+1\n", LevelDebug + 1},
 	} {
 		var got Level
 		if err := got.parse(test.in); err != nil {
@@ -157,6 +262,16 @@ func TestLevelParseError(t *testing.T) {
 		{"INFO+", "invalid syntax"},
 		{"INFO-", "invalid syntax"},
 		{"ERROR+23x", "invalid syntax"},
+		{"INFO 2", "invalid syntax"},
+		{"INFO2", "invalid syntax"},
+		{"INFO +2", "invalid syntax"},
+		{"INFO+ 2", "invalid syntax"},
+		{"INFO+2x", "invalid syntax"},
+		{"INFO+2 x", "invalid syntax"},
+		{"IN FO", "invalid syntax"},
+		{"INFO_X", "invalid syntax"},
+		{"+2", "unknown name"},
+		{" ", "unknown name"},
 	} {
 		var l Level
 
@@ -166,3 +281,201 @@ func TestLevelParseError(t *testing.T) {
 		}
 	}
 }
+
+func TestLevelOffset(t *testing.T) {
+	t.Parallel()
+
+	if got := LevelInfo.Offset(-4); got != LevelDebug {
+		t.Errorf("got %s, want %s", got, LevelDebug)
+	}
+
+	if got := LevelDebug.Offset(-4); got != LevelTrace {
+		t.Errorf("got %s, want %s", got, LevelTrace)
+	}
+
+	if got := LevelWarn.Offset(0); got != LevelWarn {
+		t.Errorf("got %s, want %s", got, LevelWarn)
+	}
+}
+
+func TestLevelAddSub(t *testing.T) {
+	t.Parallel()
+
+	for _, test := range []struct {
+		got, want Level
+	}{
+		{LevelInfo.Add(4), LevelWarn},
+		{LevelInfo.Add(-4), LevelDebug},
+		{LevelWarn.Sub(3), LevelInfo + 1},
+		{LevelDebug.Sub(4), LevelTrace},
+		{LevelError.Sub(-1), LevelError + 1},
+		{LevelTrace.Add(2).Sub(2), LevelTrace},
+	} {
+		if test.got != test.want {
+			t.Errorf("got %s, want %s", test.got, test.want)
+		}
+	}
+}
+
+func TestParseLevelRelative(t *testing.T) {
+	t.Parallel()
+
+	for _, test := range []struct {
+		in   string
+		base Level
+		want Level
+	}{
+		{"+2", LevelInfo, LevelInfo + 2},
+		{"-4", LevelInfo, LevelDebug},
+		{"-8", LevelInfo, LevelTrace},
+		{"+0", LevelWarn, LevelWarn},
+		{"debug", LevelError, LevelDebug},
+		{"INFO+2", LevelError, LevelInfo + 2},
+		{" +2 ", LevelInfo, LevelInfo + 2},
+		{"\t-4\n", LevelWarn, LevelInfo},
+		{" debug ", LevelError, LevelDebug},
+	} {
+		got, err := ParseLevelRelative(test.in, test.base)
+		if err != nil {
+			t.Fatalf("%q: %v", test.in, err)
+		}
+
+		if got != test.want {
+			t.Errorf("%q relative to %s: got %s, want %s", test.in, test.base, got, test.want)
+		}
+	}
+
+	for _, in := range []string{"", "+", "-x", "+2x", "dbg"} {
+		if _, err := ParseLevelRelative(in, LevelInfo); err == nil {
+			t.Errorf("%q: want error", in)
+		}
+	}
+
+	for _, in := range []string{"+", " -x", "+2x "} {
+		_, err := ParseLevelRelative(in, LevelInfo)
+		if !errors.Is(err, errNotInteger) || !errors.Is(err, strconv.ErrSyntax) {
+			t.Errorf("%q: got %v, want %v and %v", in, err, errNotInteger, strconv.ErrSyntax)
+		}
+	}
+}
+
+func TestLevelStringTable(t *testing.T) {
+	t.Parallel()
+
+	table := LevelStringTable(LevelTrace-1, LevelError+1)
+	if got, want := len(table), int(LevelError-LevelTrace)+3; got != want {
+		t.Fatalf("got %d levels, want %d", got, want)
+	}
+
+	for l, want := range map[Level]string{
+		LevelTrace - 1: "TRACE-1",
+		LevelTrace:     "TRACE",
+		LevelDebug - 1: "TRACE+3",
+		LevelDebug:     "DEBUG",
+		LevelInfo - 1:  "DEBUG+3",
+		LevelInfo:      "INFO",
+		LevelWarn - 2:  "INFO+2",
+		LevelWarn:      "WARN",
+		LevelError - 1: "WARN+3",
+		LevelError + 1: "ERROR+1",
+	} {
+		if got := table[l]; got != want {
+			t.Errorf("%d: got %q, want %q", l, got, want)
+		}
+	}
+
+	for l, s := range table {
+		var got Level
+		if err := got.parse(s); err != nil || got != l {
+			t.Errorf("%q: got %d, %v, want %d", s, got, err, l)
+		}
+	}
+
+	if got := LevelStringTable(LevelInfo, LevelDebug); len(got) != 0 {
+		t.Errorf("got %v, want empty table", got)
+	}
+}
+
+func TestParseLevel(t *testing.T) {
+	t.Parallel()
+
+	if got, err := ParseLevel("warn-1"); err != nil || got != LevelWarn-1 {
+		t.Errorf("got %s, %v, want %s", got, err, LevelWarn-1)
+	}
+
+	if _, err := ParseLevel("loud"); err == nil || !strings.Contains(err.Error(), "unknown name") {
+		t.Errorf("got %v, want string containing %q", err, "unknown name")
+	}
+}
+
+func TestParseLevelStrict(t *testing.T) {
+	t.Parallel()
+
+	for _, test := range []struct {
+		in   string
+		want Level
+	}{
+		{"TRACE", LevelTrace},
+		{"debug", LevelDebug},
+		{"Info", LevelInfo},
+		{"wArN", LevelWarn},
+		{"ERROR", LevelError},
+	} {
+		got, err := ParseLevelStrict(test.in)
+		if err != nil || got != test.want {
+			t.Errorf("%q: got %s, %v, want %s", test.in, got, err, test.want)
+		}
+	}
+
+	for _, in := range []string{
+		"INFO+2",
+		"warn-1",
+		"INFO+0",
+		" INFO",
+		"INFO\n",
+		"INFO2",
+		"",
+		"+2",
+		"loud",
+	} {
+		_, err := ParseLevelStrict(in)
+		if err == nil || !strings.Contains(err.Error(), "unknown name") {
+			t.Errorf("%q: got %v, want string containing %q", in, err, "unknown name")
+		}
+	}
+}
+
+func TestLevelFromEnv(t *testing.T) { //nolint:paralleltest // uses t.Setenv
+	for _, test := range []struct {
+		value   string
+		want    Level
+		wantErr bool
+	}{
+		{"", LevelWarn, false},
+		{"debug", LevelDebug, false},
+		{"TRACE+1", LevelTrace + 1, false},
+		{"verbose", LevelWarn, true},
+	} {
+		t.Setenv("REGINALD_TEST_LOG_LEVEL", test.value)
+
+		if got := LevelFromEnv("REGINALD_TEST_LOG_LEVEL", LevelWarn); got != test.want {
+			t.Errorf("LevelFromEnv(%q): got %s, want %s", test.value, got, test.want)
+		}
+
+		got, err := LookupLevelEnv("REGINALD_TEST_LOG_LEVEL", LevelWarn)
+		if got != test.want || (err != nil) != test.wantErr {
+			t.Errorf(
+				"LookupLevelEnv(%q): got %s, %v, want %s, error %t",
+				test.value,
+				got,
+				err,
+				test.want,
+				test.wantErr,
+			)
+		}
+	}
+
+	if got := LevelFromEnv("REGINALD_TEST_LOG_LEVEL_UNSET", LevelError); got != LevelError {
+		t.Errorf("unset: got %s, want %s", got, LevelError)
+	}
+}
@@ -0,0 +1,116 @@
+// Copyright 2025 Antti Kivi
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logs
+
+import (
+	"log/slog"
+	"strings"
+)
+
+// The supported presets for [ReplaceAttrPreset].
+const (
+	// StyleGCP follows the structured logging conventions of Google Cloud
+	// Logging: the level is written as "severity" using the Cloud Logging
+	// severity names and the message is written as "message".
+	StyleGCP Style = iota + 1
+
+	// StyleECS follows the Elastic Common Schema: the time is written as
+	// "@timestamp", the level as "log.level" in lowercase, and the message as
+	// "message".
+	StyleECS
+)
+
+// Style is a convention for naming the standard fields of log records. It
+// selects the preset returned by [ReplaceAttrPreset].
+type Style int
+
+// ReplaceAttrPreset returns a function that can be used as the ReplaceAttr
+// option of the [log/slog] handlers to rename the standard keys of the records
+// and to map the level to the representation expected by the given style. Only
+// the top-level standard attributes are changed and the other attributes are
+// returned as is. For an unknown style, the returned function changes nothing.
+func ReplaceAttrPreset(style Style) func(groups []string, a slog.Attr) slog.Attr {
+	return func(groups []string, a slog.Attr) slog.Attr {
+		if len(groups) > 0 {
+			return a
+		}
+
+		switch style {
+		case StyleGCP:
+			return replaceGCP(a)
+		case StyleECS:
+			return replaceECS(a)
+		default:
+			return a
+		}
+	}
+}
+
+func replaceGCP(a slog.Attr) slog.Attr {
+	switch a.Key {
+	case slog.LevelKey:
+		return slog.String("severity", gcpSeverity(attrLevel(a)))
+	case slog.MessageKey:
+		return slog.Attr{Key: "message", Value: a.Value}
+	default:
+		return a
+	}
+}
+
+func replaceECS(a slog.Attr) slog.Attr {
+	switch a.Key {
+	case slog.TimeKey:
+		return slog.Attr{Key: "@timestamp", Value: a.Value}
+	case slog.LevelKey:
+		return slog.String("log.level", strings.ToLower(attrLevel(a).String()))
+	case slog.MessageKey:
+		return slog.Attr{Key: "message", Value: a.Value}
+	default:
+		return a
+	}
+}
+
+// attrLevel returns the level stored in the level attribute a.
+func attrLevel(a slog.Attr) Level {
+	switch v := a.Value.Any().(type) {
+	case slog.Level:
+		return Level(v)
+	case Level:
+		return v
+	default:
+		var l Level
+		if err := l.parse(a.Value.String()); err != nil {
+			return LevelInfo
+		}
+
+		return l
+	}
+}
+
+// gcpSeverity returns the Cloud Logging severity name for the level.
+func gcpSeverity(l Level) string {
+	switch {
+	case l < LevelInfo:
+		return "DEBUG"
+	case l < LevelWarn:
+		return "INFO"
+	case l < LevelError:
+		return "WARNING"
+	case l < LevelError+4:
+		return "ERROR"
+	default:
+		return "CRITICAL"
+	}
+}
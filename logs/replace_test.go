@@ -0,0 +1,135 @@
+// Copyright 2025 Antti Kivi
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logs_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"reflect"
+	"testing"
+
+	"github.com/reginald-project/reginald-sdk-go/logs"
+)
+
+func TestReplaceAttrPreset(t *testing.T) {
+	t.Parallel()
+
+	//nolint:govet // don't care about this in tests
+	for _, test := range []struct {
+		style  logs.Style
+		groups []string
+		in     slog.Attr
+		want   slog.Attr
+	}{
+		{
+			logs.StyleGCP,
+			nil,
+			slog.Any(slog.LevelKey, slog.LevelWarn),
+			slog.String("severity", "WARNING"),
+		},
+		{
+			logs.StyleGCP,
+			nil,
+			slog.Any(slog.LevelKey, logs.LevelTrace.Level()),
+			slog.String("severity", "DEBUG"),
+		},
+		{
+			logs.StyleGCP,
+			nil,
+			slog.Any(slog.LevelKey, slog.LevelInfo+2),
+			slog.String("severity", "INFO"),
+		},
+		{
+			logs.StyleGCP,
+			nil,
+			slog.Any(slog.LevelKey, slog.LevelError),
+			slog.String("severity", "ERROR"),
+		},
+		{
+			logs.StyleGCP,
+			nil,
+			slog.Any(slog.LevelKey, slog.LevelError+4),
+			slog.String("severity", "CRITICAL"),
+		},
+		{logs.StyleGCP, nil, slog.String(slog.MessageKey, "hi"), slog.String("message", "hi")},
+		{logs.StyleGCP, nil, slog.Int("n", 1), slog.Int("n", 1)},
+		{
+			logs.StyleGCP,
+			[]string{"g"},
+			slog.String(slog.MessageKey, "hi"),
+			slog.String(slog.MessageKey, "hi"),
+		},
+		{
+			logs.StyleECS,
+			nil,
+			slog.Any(slog.LevelKey, logs.LevelTrace.Level()),
+			slog.String("log.level", "trace"),
+		},
+		{
+			logs.StyleECS,
+			nil,
+			slog.Any(slog.LevelKey, slog.LevelInfo+1),
+			slog.String("log.level", "info+1"),
+		},
+		{logs.StyleECS, nil, slog.String(slog.MessageKey, "hi"), slog.String("message", "hi")},
+		{logs.StyleECS, nil, slog.String(slog.TimeKey, "now"), slog.String("@timestamp", "now")},
+		{
+			logs.Style(0),
+			nil,
+			slog.String(slog.MessageKey, "hi"),
+			slog.String(slog.MessageKey, "hi"),
+		},
+	} {
+		got := logs.ReplaceAttrPreset(test.style)(test.groups, test.in)
+		if !got.Equal(test.want) {
+			t.Errorf(
+				"%d, %v, %v: got %v, want %v",
+				test.style,
+				test.groups,
+				test.in,
+				got,
+				test.want,
+			)
+		}
+	}
+}
+
+func TestReplaceAttrPresetHandler(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+
+	h := slog.NewJSONHandler(&buf, &slog.HandlerOptions{
+		Level:       logs.LevelTrace,
+		ReplaceAttr: logs.ReplaceAttrPreset(logs.StyleGCP),
+	})
+
+	r := slog.NewRecord(testTime, logs.LevelTrace.Level(), "hello", 0)
+	if err := h.Handle(context.Background(), r); err != nil {
+		t.Fatal(err)
+	}
+
+	var got map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+		t.Fatal(err)
+	}
+
+	want := map[string]any{"time": "2025-06-01T12:30:00Z", "severity": "DEBUG", "message": "hello"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
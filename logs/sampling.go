@@ -0,0 +1,251 @@
+// Copyright 2025 Antti Kivi
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logs
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+// Default values for SamplingOptions.
+const (
+	DefaultSamplingWindow = time.Second
+	DefaultSamplingBurst  = 100
+)
+
+// DroppedKey is the key of the attribute that holds the number of dropped
+// records in the summary records written by SamplingHandler.
+const DroppedKey = "dropped"
+
+// SamplingOptions are the options for a SamplingHandler. A zero
+// SamplingOptions consists entirely of the default values.
+type SamplingOptions struct {
+	// Window is the length of the time window in which at most Burst records
+	// of each level are passed on. If Window is zero or negative,
+	// [DefaultSamplingWindow] is used.
+	Window time.Duration
+
+	// Burst is the number of records of each level that are passed on in every
+	// window. The rest of the records of the level in the window are dropped.
+	// If Burst is zero or negative, [DefaultSamplingBurst] is used.
+	Burst int
+}
+
+// SamplingHandler is a [slog.Handler] that limits the rate of the records
+// passed on to another handler. In every time window, it passes on the first
+// records of each level up to the burst size and drops the rest. When
+// a window in which records of a level were dropped ends, it passes on
+// a summary record at that level with the message "dropped log records" and
+// the number of dropped records as [DroppedKey]. The summary is written when
+// the window ends even if no more records of the level arrive, so the dropped
+// records are reported also after a flood stops. The handlers returned by
+// WithAttrs and WithGroup share the limits with the handler they were created
+// from.
+type SamplingHandler struct {
+	inner slog.Handler
+	state *samplingState
+}
+
+// samplingState is the state shared by a SamplingHandler and the handlers
+// derived from it.
+type samplingState struct {
+	mu     sync.Mutex
+	root   slog.Handler // handler for the summary records without groups or attrs
+	window time.Duration
+	burst  int
+	now    func() time.Time
+	levels map[slog.Level]*levelWindow
+	err    error // error from a summary written by a timer
+}
+
+// levelWindow is the sampling window of a single level.
+type levelWindow struct {
+	start   time.Time
+	count   int
+	dropped int
+	timer   *time.Timer // writes the summary at the end of the window
+}
+
+// NewSamplingHandler returns a new [SamplingHandler] that passes the sampled
+// records on to inner using the given options. If opts is nil, the default
+// options are used.
+func NewSamplingHandler(inner slog.Handler, opts *SamplingOptions) *SamplingHandler {
+	var o SamplingOptions
+
+	if opts != nil {
+		o = *opts
+	}
+
+	if o.Window <= 0 {
+		o.Window = DefaultSamplingWindow
+	}
+
+	if o.Burst <= 0 {
+		o.Burst = DefaultSamplingBurst
+	}
+
+	state := &samplingState{ //nolint:exhaustruct // mu has a zero value
+		root:   inner,
+		window: o.Window,
+		burst:  o.Burst,
+		now:    time.Now,
+		levels: make(map[slog.Level]*levelWindow),
+	}
+
+	return &SamplingHandler{inner: inner, state: state}
+}
+
+// Enabled reports whether the inner handler handles records at the given
+// level.
+func (h *SamplingHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.inner.Enabled(ctx, level)
+}
+
+// Handle passes the record on to the inner handler unless the limit of its
+// level has been reached in the current window. If writing a summary at
+// the end of a window has failed since the last call, the error is returned
+// after the record has been handled.
+//
+//nolint:gocritic // implements interface
+func (h *SamplingHandler) Handle(ctx context.Context, r slog.Record) error {
+	keep, dropped, deferred := h.state.sample(r.Level)
+
+	if dropped > 0 {
+		if err := h.state.summarize(ctx, r.Time, r.Level, dropped); err != nil {
+			return err
+		}
+	}
+
+	if keep {
+		if err := h.inner.Handle(ctx, r); err != nil {
+			return fmt.Errorf("%w", err)
+		}
+	}
+
+	return deferred
+}
+
+// WithAttrs returns a new [SamplingHandler] whose inner handler has the given
+// attributes.
+func (h *SamplingHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &SamplingHandler{inner: h.inner.WithAttrs(attrs), state: h.state}
+}
+
+// WithGroup returns a new [SamplingHandler] whose inner handler has the given
+// group.
+func (h *SamplingHandler) WithGroup(name string) slog.Handler {
+	return &SamplingHandler{inner: h.inner.WithGroup(name), state: h.state}
+}
+
+// sample reports whether a record at the given level should be kept and
+// returns the number of dropped records to report, if a new window started
+// before the timer of the previous window reported them. It also returns and
+// clears the error from the last summary written by a timer.
+func (s *samplingState) sample(level slog.Level) (bool, int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	err := s.err
+	s.err = nil
+	now := s.now()
+
+	w, ok := s.levels[level]
+	if !ok {
+		w = &levelWindow{start: now, count: 0, dropped: 0, timer: nil}
+		s.levels[level] = w
+	}
+
+	dropped := 0
+
+	if now.Sub(w.start) >= s.window {
+		if w.timer != nil {
+			w.timer.Stop()
+			w.timer = nil
+		}
+
+		dropped = w.dropped
+		w.start, w.count, w.dropped = now, 0, 0
+	}
+
+	if w.count >= s.burst {
+		w.dropped++
+
+		if w.timer == nil {
+			start := w.start
+			w.timer = time.AfterFunc(
+				start.Add(s.window).Sub(now),
+				func() { s.flush(level, w, start) },
+			)
+		}
+
+		return false, dropped, err
+	}
+
+	w.count++
+
+	return true, dropped, err
+}
+
+// flush is run by the timer of the window of the given level that started at
+// start when the window ends. It writes the summary of the records dropped in
+// the window unless a new window has already started and reported them. As
+// there is no caller to return the error to, it is returned by the next call
+// to Handle.
+func (s *samplingState) flush(level slog.Level, w *levelWindow, start time.Time) {
+	s.mu.Lock()
+
+	if !w.start.Equal(start) {
+		s.mu.Unlock()
+
+		return
+	}
+
+	dropped := w.dropped
+	w.dropped = 0
+	w.timer = nil
+	now := s.now()
+
+	s.mu.Unlock()
+
+	if dropped == 0 {
+		return
+	}
+
+	if err := s.summarize(context.Background(), now, level, dropped); err != nil {
+		s.mu.Lock()
+		s.err = err
+		s.mu.Unlock()
+	}
+}
+
+// summarize writes the summary record of the dropped records.
+func (s *samplingState) summarize(
+	ctx context.Context,
+	t time.Time,
+	level slog.Level,
+	dropped int,
+) error {
+	summary := slog.NewRecord(t, level, "dropped log records", 0)
+	summary.AddAttrs(slog.Int(DroppedKey, dropped))
+
+	if err := s.root.Handle(ctx, summary); err != nil {
+		return fmt.Errorf("%w", err)
+	}
+
+	return nil
+}
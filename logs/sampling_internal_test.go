@@ -0,0 +1,190 @@
+// Copyright 2025 Antti Kivi
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logs
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestSamplingHandler(t *testing.T) {
+	t.Parallel()
+
+	type record struct {
+		Level   Level  `json:"level"`
+		Msg     string `json:"msg"`
+		Dropped int    `json:"dropped"`
+	}
+
+	var (
+		buf  bytes.Buffer
+		mu   sync.Mutex
+		now  = time.Date(2025, time.June, 1, 12, 30, 0, 0, time.UTC)
+		recs []record
+	)
+
+	h := NewSamplingHandler(NewHandler(&buf, &HandlerOptions{Level: LevelDebug}), &SamplingOptions{
+		Window: time.Second,
+		Burst:  3,
+	})
+	h.state.now = func() time.Time {
+		mu.Lock()
+		defer mu.Unlock()
+
+		return now
+	}
+
+	logger := slog.New(h).With("a", 1)
+
+	for range 10 {
+		logger.Info("flood")
+	}
+
+	logger.Debug("other level")
+
+	mu.Lock()
+	now = now.Add(time.Second)
+	mu.Unlock()
+
+	logger.Info("after")
+
+	for line := range strings.Lines(buf.String()) {
+		var rec record
+
+		if err := json.Unmarshal([]byte(line), &rec); err != nil {
+			t.Fatalf("invalid record %q: %v", line, err)
+		}
+
+		recs = append(recs, rec)
+	}
+
+	var msgs []string
+
+	for _, rec := range recs {
+		msgs = append(msgs, rec.Msg)
+	}
+
+	want := []string{"flood", "flood", "flood", "other level", "dropped log records", "after"}
+	if strings.Join(msgs, "|") != strings.Join(want, "|") {
+		t.Fatalf("got %q, want %q", msgs, want)
+	}
+
+	if summary := recs[4]; summary.Level != LevelInfo || summary.Dropped != 7 {
+		t.Errorf("got summary %+v, want 7 dropped INFO records", summary)
+	}
+}
+
+func TestSamplingHandlerFloodStops(t *testing.T) {
+	t.Parallel()
+
+	var buf syncWriter
+
+	h := NewSamplingHandler(
+		NewHandler(&buf, nil),
+		&SamplingOptions{Window: 50 * time.Millisecond, Burst: 2},
+	)
+	logger := slog.New(h)
+
+	for range 5 {
+		logger.Info("flood")
+	}
+
+	deadline := time.Now().Add(5 * time.Second)
+	for !strings.Contains(buf.String(), "dropped log records") {
+		if time.Now().After(deadline) {
+			t.Fatalf("no summary after the flood stopped, got %q", buf.String())
+		}
+
+		time.Sleep(time.Millisecond)
+	}
+
+	lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("got %d records, want 3: %q", len(lines), lines)
+	}
+
+	var summary struct {
+		Level   Level `json:"level"`
+		Dropped int   `json:"dropped"`
+	}
+
+	if err := json.Unmarshal([]byte(lines[2]), &summary); err != nil {
+		t.Fatal(err)
+	}
+
+	if summary.Level != LevelInfo || summary.Dropped != 3 {
+		t.Errorf("got summary %+v, want 3 dropped INFO records", summary)
+	}
+
+	logger.Info("again")
+
+	if got := strings.Count(buf.String(), "dropped log records"); got != 1 {
+		t.Errorf("got %d summaries, want 1", got)
+	}
+}
+
+func TestSamplingHandlerConcurrent(t *testing.T) {
+	t.Parallel()
+
+	var (
+		buf syncWriter
+		wg  sync.WaitGroup
+	)
+
+	h := NewSamplingHandler(NewHandler(&buf, nil), &SamplingOptions{Window: time.Hour, Burst: 50})
+
+	for range 8 {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			for range 100 {
+				slog.New(h).Info("msg")
+			}
+		}()
+	}
+
+	wg.Wait()
+
+	if got := strings.Count(buf.String(), "\n"); got != 50 {
+		t.Errorf("got %d records, want 50", got)
+	}
+}
+
+// syncWriter is a bytes.Buffer that is safe for concurrent use.
+type syncWriter struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (w *syncWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	return w.buf.Write(p)
+}
+
+func (w *syncWriter) String() string {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	return w.buf.String()
+}
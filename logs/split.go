@@ -0,0 +1,90 @@
+// Copyright 2025 Antti Kivi
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logs
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+)
+
+// splitHandler is a [slog.Handler] that passes the records at or above
+// the threshold to high and the rest to low.
+type splitHandler struct {
+	low       slog.Handler
+	high      slog.Handler
+	threshold slog.Level
+}
+
+// NewSplitHandler returns a new [slog.Handler] that writes the records at or
+// above the threshold to high and the rest to low, for example, WARN and ERROR
+// to the standard error and the lower levels to the standard output. Both
+// writers get the records in the format of [NewHandler] with the given
+// options, and HandlerOptions.Level still sets the minimum level of all of
+// the records. Every record is written to exactly one of the writers. If opts
+// is nil, the default options are used.
+func NewSplitHandler(low, high io.Writer, threshold Level, opts *HandlerOptions) slog.Handler {
+	return &splitHandler{
+		low:       NewHandler(low, opts),
+		high:      NewHandler(high, opts),
+		threshold: threshold.Level(),
+	}
+}
+
+// Enabled reports whether the handler handles records at the given level.
+func (h *splitHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.handler(level).Enabled(ctx, level)
+}
+
+// Handle passes the record on to the handler of its level.
+//
+//nolint:gocritic // implements interface
+func (h *splitHandler) Handle(ctx context.Context, r slog.Record) error {
+	if err := h.handler(r.Level).Handle(ctx, r); err != nil {
+		return fmt.Errorf("%w", err)
+	}
+
+	return nil
+}
+
+// WithAttrs returns a new handler whose both underlying handlers have
+// the given attributes.
+func (h *splitHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &splitHandler{
+		low:       h.low.WithAttrs(attrs),
+		high:      h.high.WithAttrs(attrs),
+		threshold: h.threshold,
+	}
+}
+
+// WithGroup returns a new handler whose both underlying handlers have
+// the given group.
+func (h *splitHandler) WithGroup(name string) slog.Handler {
+	return &splitHandler{
+		low:       h.low.WithGroup(name),
+		high:      h.high.WithGroup(name),
+		threshold: h.threshold,
+	}
+}
+
+// handler returns the underlying handler for the records at the given level.
+func (h *splitHandler) handler(level slog.Level) slog.Handler {
+	if level >= h.threshold {
+		return h.high
+	}
+
+	return h.low
+}
@@ -0,0 +1,104 @@
+// Copyright 2025 Antti Kivi
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logs_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/reginald-project/reginald-sdk-go/logs"
+)
+
+func TestSplitHandler(t *testing.T) {
+	t.Parallel()
+
+	for _, test := range []struct {
+		level logs.Level
+		high  bool
+	}{
+		{logs.LevelTrace, false},
+		{logs.LevelDebug, false},
+		{logs.LevelInfo, false},
+		{logs.LevelInfo.Add(3), false},
+		{logs.LevelWarn, true},
+		{logs.LevelError, true},
+		{logs.LevelError.Add(4), true},
+	} {
+		var low, high bytes.Buffer
+
+		h := logs.NewSplitHandler(
+			&low,
+			&high,
+			logs.LevelWarn,
+			&logs.HandlerOptions{Level: logs.LevelTrace},
+		)
+		logger := slog.New(h).With("a", 1).WithGroup("g")
+
+		logger.Log(context.Background(), test.level.Level(), "msg", "b", 2)
+
+		got, empty := &low, &high
+		if test.high {
+			got, empty = &high, &low
+		}
+
+		if empty.Len() != 0 || strings.Count(got.String(), "\n") != 1 {
+			t.Fatalf(
+				"%v: got low %q and high %q, want the record on one writer",
+				test.level,
+				&low,
+				&high,
+			)
+		}
+
+		var rec struct {
+			Level string         `json:"level"`
+			A     int            `json:"a"`
+			G     map[string]any `json:"g"`
+		}
+
+		if err := json.Unmarshal(got.Bytes(), &rec); err != nil {
+			t.Fatalf("%v: invalid record %q: %v", test.level, got, err)
+		}
+
+		if rec.Level != test.level.String() || rec.A != 1 || rec.G["b"] != 2.0 {
+			t.Errorf("%v: got %+v, want level %s with the attributes", test.level, rec, test.level)
+		}
+	}
+}
+
+func TestSplitHandlerLevel(t *testing.T) {
+	t.Parallel()
+
+	var low, high bytes.Buffer
+
+	logger := slog.New(logs.NewSplitHandler(&low, &high, logs.LevelError, nil))
+
+	logger.Debug("discarded")
+	logger.Info("low")
+	logger.Warn("low")
+	logger.Error("high")
+
+	if got := strings.Count(low.String(), "\n"); got != 2 {
+		t.Errorf("got %d records on low, want 2", got)
+	}
+
+	if got := strings.Count(high.String(), "\n"); got != 1 {
+		t.Errorf("got %d records on high, want 1", got)
+	}
+}